@@ -0,0 +1,147 @@
+package yagclif
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a number of bytes written in human form
+// in the cli such as 512K, 10MiB or 1.5GB.
+type ByteSize uint64
+
+// Unit of a byte size.
+type byteUnit struct {
+	name string
+	size uint64
+}
+
+// Units of byte sizes from the largest to the smallest.
+// Single letter units are binary units.
+var byteUnits = []byteUnit{
+	{"EiB", 1 << 60}, {"EB", 1e18}, {"E", 1 << 60},
+	{"PiB", 1 << 50}, {"PB", 1e15}, {"P", 1 << 50},
+	{"TiB", 1 << 40}, {"TB", 1e12}, {"T", 1 << 40},
+	{"GiB", 1 << 30}, {"GB", 1e9}, {"G", 1 << 30},
+	{"MiB", 1 << 20}, {"MB", 1e6}, {"M", 1 << 20},
+	{"KiB", 1 << 10}, {"KB", 1e3}, {"K", 1 << 10},
+	{"B", 1},
+}
+
+// String returns the size with the largest unit
+// expressing it with at most two decimals.
+func (size ByteSize) String() string {
+	bytes := uint64(size)
+	for _, unit := range byteUnits {
+		// single letter units are only parsed
+		if len(unit.name) == 1 && unit.size != 1 {
+			continue
+		}
+		// remainder*100 can overflow for the largest units
+		hi, lo := bits.Mul64(bytes%unit.size, 100)
+		hundredths, remainder := bits.Div64(hi, lo, unit.size)
+		if bytes >= unit.size && remainder == 0 {
+			value := strconv.FormatUint(bytes/unit.size, 10)
+			if hundredths != 0 {
+				decimals := fmt.Sprintf("%02d", hundredths)
+				value += "." + strings.TrimRight(decimals, "0")
+			}
+			return value + unit.name
+		}
+	}
+	return "0B"
+}
+
+// Parses a byte size such as 512K, 10MiB or 1.5GB,
+// units are case insensitive and default to bytes.
+func parseByteSize(value string) (uint64, error) {
+	trimmed := strings.TrimSpace(value)
+	numberEnd := strings.IndexFunc(trimmed, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if numberEnd == -1 {
+		numberEnd = len(trimmed)
+	}
+	number, unitName := trimmed[:numberEnd], strings.TrimSpace(trimmed[numberEnd:])
+	unitSize := uint64(0)
+	if unitName == "" {
+		unitSize = 1
+	}
+	for _, unit := range byteUnits {
+		if strings.EqualFold(unit.name, unitName) {
+			unitSize = unit.size
+			break
+		}
+	}
+	if unitSize == 0 {
+		return 0, fmt.Errorf("unknown byte size unit %s", unitName)
+	}
+	if !strings.Contains(number, ".") {
+		count, err := strconv.ParseUint(number, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		if count > math.MaxUint64/unitSize {
+			return 0, fmt.Errorf("byte size %s out of range", value)
+		}
+		return count * unitSize, nil
+	}
+	count, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, err
+	}
+	bytes := count * float64(unitSize)
+	if bytes >= math.MaxUint64 || bytes < 0 {
+		return 0, fmt.Errorf("byte size %s out of range", value)
+	}
+	if bytes != math.Trunc(bytes) {
+		return 0, fmt.Errorf("byte size %s is not a whole number of bytes", value)
+	}
+	return uint64(bytes), nil
+}
+
+// Returns a valueType parsing byte sizes
+// into values of an integer type.
+func newByteSizeValueType(tipe reflect.Type) *valueType {
+	return &valueType{
+		metavar: "size",
+		parse: func(p *parameter, value string) (reflect.Value, error) {
+			parsed := reflect.New(tipe).Elem()
+			bytes, err := parseByteSize(value)
+			if err != nil {
+				return parsed, err
+			}
+			switch tipe.Kind() {
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				if parsed.OverflowUint(bytes) {
+					return parsed, fmt.Errorf("byte size %s out of range for %s", value, tipe)
+				}
+				parsed.SetUint(bytes)
+			default:
+				if bytes > math.MaxInt64 || parsed.OverflowInt(int64(bytes)) {
+					return parsed, fmt.Errorf("byte size %s out of range for %s", value, tipe)
+				}
+				parsed.SetInt(int64(bytes))
+			}
+			return parsed, nil
+		},
+		format: func(p *parameter, value reflect.Value) string {
+			switch value.Kind() {
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				return ByteSize(value.Uint()).String()
+			}
+			if value.Int() < 0 {
+				return formatSprint(p, value)
+			}
+			return ByteSize(value.Int()).String()
+		},
+	}
+}
+
+func init() {
+	byteSizeType := reflect.TypeOf(ByteSize(0))
+	registeredTypes[byteSizeType] = newByteSizeValueType(byteSizeType)
+}
@@ -0,0 +1,100 @@
+package yagclif
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteSize(t *testing.T) {
+	t.Run("works", func(t *testing.T) {
+		for value, expected := range map[string]uint64{
+			"42":     42,
+			"42B":    42,
+			"512K":   512 << 10,
+			"512k":   512 << 10,
+			"10MiB":  10 << 20,
+			"10 mb":  10e6,
+			"1.5GB":  1.5e9,
+			"1.5G":   3 << 29,
+			"0.5KiB": 512,
+		} {
+			size, err := parseByteSize(value)
+			assert.Nil(t, err, value)
+			assert.Equal(t, expected, size, value)
+		}
+	})
+	t.Run("returns errors", func(t *testing.T) {
+		for _, value := range []string{
+			"", "K", "12X", "1.5.5M", "0.3B", "16EiB", "18446744073709551616", "-1K",
+		} {
+			_, err := parseByteSize(value)
+			assert.NotNil(t, err, value)
+		}
+	})
+}
+
+func TestByteSizeString(t *testing.T) {
+	for size, expected := range map[ByteSize]string{
+		0:                  "0B",
+		42:                 "42B",
+		512 << 10:          "512KiB",
+		10 << 20:           "10MiB",
+		1.5e9:              "1.5GB",
+		1536:               "1.5KiB",
+		1001:               "1001B",
+		math.MaxUint64:     "18446744073709551615B",
+		ByteSize(3 << 60):  "3EiB",
+		ByteSize(25 << 58): "6.25EiB",
+	} {
+		assert.Equal(t, expected, size.String(), uint64(size))
+	}
+}
+
+func TestByteSizeParameters(t *testing.T) {
+	type foo struct {
+		Cache   ByteSize `yagclif:"default:64MiB"`
+		Buffer  int      `yagclif:"bytesize;default:4K"`
+		Small   uint8    `yagclif:"bytesize"`
+		Chunks  []int64  `yagclif:"bytesize;delimiter:,"`
+		Maximum *uint64  `yagclif:"bytesize"`
+	}
+	fooType := reflect.TypeOf(foo{})
+	t.Run("works", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		_, err = params.ParseArguments(fooVar, []string{
+			"--small", "255B", "--chunks", "1K,1.5MB", "--maximum", "1GiB",
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, ByteSize(64<<20), fooVar.Cache)
+		assert.Equal(t, 4096, fooVar.Buffer)
+		assert.Equal(t, uint8(255), fooVar.Small)
+		assert.Equal(t, []int64{1024, 1.5e6}, fooVar.Chunks)
+		assert.Equal(t, uint64(1<<30), *fooVar.Maximum)
+	})
+	t.Run("returns range errors", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		_, err = params.ParseArguments(&foo{}, []string{"--small", "1K"})
+		assert.NotNil(t, err)
+	})
+	t.Run("help shows human defaults", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		assert.Contains(t, params[0].GetHelp(), "--cache size (default=64MiB)")
+		assert.Contains(t, params[1].GetHelp(), "--buffer size (default=4KiB)")
+		assert.Contains(t, params[3].GetHelp(), "--chunks []size")
+		assert.Contains(t, params[4].GetHelp(), "--maximum *size")
+	})
+	t.Run("bytesize on non integer type", func(t *testing.T) {
+		type bar struct {
+			A string `yagclif:"bytesize"`
+		}
+		_, err := newParameters(reflect.TypeOf(bar{}))
+		assert.NotNil(t, err)
+	})
+}
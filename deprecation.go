@@ -0,0 +1,37 @@
+package yagclif
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// Writer the deprecation warnings are printed to.
+var (
+	warningLock   sync.RWMutex
+	warningOutput io.Writer = os.Stderr
+)
+
+// SetWarningOutput sets the writer deprecation warnings
+// are printed to, os.Stderr by default.
+// Warnings are discarded if the writer is nil.
+func SetWarningOutput(w io.Writer) {
+	if w == nil {
+		w = ioutil.Discard
+	}
+	warningLock.Lock()
+	defer warningLock.Unlock()
+	warningOutput = w
+}
+
+// Prints a warning if the parameter is deprecated.
+func (p *parameter) warnIfDeprecated() {
+	if p.deprecated == "" {
+		return
+	}
+	warningLock.RLock()
+	defer warningLock.RUnlock()
+	fmt.Fprintf(warningOutput, "warning : %s is deprecated, %s\r\n", p.CliNames()[0], p.deprecated)
+}
@@ -0,0 +1,99 @@
+package yagclif
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type deprecatedFlags struct {
+	Output  string `yagclif:"shortname:o"`
+	Out     string `yagclif:"deprecated:use --output"`
+	Color   bool   `yagclif:"shortname:c;deprecated:colors are always enabled"`
+	Verbose int    `yagclif:"count;shortname:v"`
+}
+
+func TestSetWarningOutput(t *testing.T) {
+	defer SetWarningOutput(os.Stderr)
+	param := &parameter{name: "Out", deprecated: "use --output"}
+	var buffer bytes.Buffer
+	SetWarningOutput(&buffer)
+	param.warnIfDeprecated()
+	assert.Equal(t, "warning : --out is deprecated, use --output\r\n", buffer.String())
+	SetWarningOutput(nil)
+	param.warnIfDeprecated()
+	assert.Equal(t, "warning : --out is deprecated, use --output\r\n", buffer.String())
+	param.deprecated = ""
+	SetWarningOutput(&buffer)
+	param.warnIfDeprecated()
+	assert.Equal(t, "warning : --out is deprecated, use --output\r\n", buffer.String())
+}
+
+func TestParseDeprecatedArguments(t *testing.T) {
+	defer SetWarningOutput(os.Stderr)
+	fooType := reflect.TypeOf(deprecatedFlags{})
+	for _, testCase := range []struct {
+		args     []string
+		expected deprecatedFlags
+		warnings []string
+	}{
+		{[]string{"--output", "a"}, deprecatedFlags{Output: "a"}, nil},
+		{[]string{"--out", "a"}, deprecatedFlags{Out: "a"}, []string{"--out"}},
+		{[]string{"--out=a", "--color"}, deprecatedFlags{Out: "a", Color: true}, []string{"--out", "--color"}},
+		{[]string{"--no-color"}, deprecatedFlags{}, []string{"--color"}},
+		{[]string{"-vc"}, deprecatedFlags{Verbose: 1, Color: true}, []string{"--color"}},
+	} {
+		var buffer bytes.Buffer
+		SetWarningOutput(&buffer)
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &deprecatedFlags{}
+		_, err = params.ParseArguments(fooVar, testCase.args)
+		assert.Nil(t, err, testCase.args)
+		assert.Equal(t, testCase.expected, *fooVar, testCase.args)
+		warnings := strings.Split(strings.TrimSuffix(buffer.String(), "\r\n"), "\r\n")
+		if testCase.warnings == nil {
+			assert.Empty(t, buffer.String(), testCase.args)
+			continue
+		}
+		assert.Len(t, warnings, len(testCase.warnings), testCase.args)
+		for i, name := range testCase.warnings {
+			assert.True(t, strings.HasPrefix(warnings[i], "warning : "+name+" is deprecated, "), warnings[i])
+		}
+	}
+}
+
+func TestDeprecatedHelp(t *testing.T) {
+	params, err := newParameters(reflect.TypeOf(deprecatedFlags{}))
+	assert.Nil(t, err)
+	help := strings.Join(params.getHelp(false), "\r\n")
+	assert.Contains(t, help, "--output")
+	assert.NotContains(t, help, "--out ")
+	assert.NotContains(t, help, "color")
+	help = strings.Join(params.getHelp(true), "\r\n")
+	assert.Contains(t, help, "--out string (deprecated=use --output)")
+	assert.Contains(t, help, "--[no-]color -c bool (deprecated=colors are always enabled)")
+	t.Run("app verbose help", func(t *testing.T) {
+		app := NewCliApp("app", "")
+		err := app.AddRoute("run", "", func(deprecatedFlags, []string) {})
+		assert.Nil(t, err)
+		assert.NotContains(t, app.GetHelp(), "--out ")
+		assert.Contains(t, app.GetVerboseHelp(), "--out string (deprecated=use --output)")
+	})
+	t.Run("invalid definitions", func(t *testing.T) {
+		type foo struct {
+			A string `yagclif:"deprecated"`
+			B string `yagclif:"deprecated:x;mandatory"`
+			C string `yagclif:"deprecated:x;positional:0"`
+		}
+		for i := 0; i < 3; i++ {
+			param, err := newParameter(reflect.TypeOf(foo{}).Field(i))
+			assert.NotNil(t, err)
+			assert.Nil(t, param)
+		}
+	})
+}
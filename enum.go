@@ -0,0 +1,84 @@
+package yagclif
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Name of the method listing the valid values of an enum type.
+const enumValuesMethod = "Values"
+
+// Type of the fmt.Stringer interface.
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// Returns if the type is an enum : a type implementing
+// fmt.Stringer with a method Values returning a slice
+// of its valid values.
+func isEnumType(tipe reflect.Type) bool {
+	if !tipe.Implements(stringerType) {
+		return false
+	}
+	method, exists := tipe.MethodByName(enumValuesMethod)
+	if !exists {
+		return false
+	}
+	methodType := method.Type
+	// the receiver is the first input.
+	return methodType.NumIn() == 1 && methodType.NumOut() == 1 &&
+		methodType.Out(0) == reflect.SliceOf(tipe)
+}
+
+// Returns the valid values of an enum type.
+func enumValues(tipe reflect.Type) []reflect.Value {
+	method, _ := tipe.MethodByName(enumValuesMethod)
+	receiver := reflect.New(tipe).Elem()
+	values := method.Func.Call([]reflect.Value{receiver})[0]
+	enumValues := make([]reflect.Value, values.Len())
+	for i := range enumValues {
+		enumValues[i] = values.Index(i)
+	}
+	return enumValues
+}
+
+// Returns the names of the valid values of an enum type.
+func enumNames(tipe reflect.Type) []string {
+	values := enumValues(tipe)
+	names := make([]string, len(values))
+	for i, value := range values {
+		names[i] = value.Interface().(fmt.Stringer).String()
+	}
+	return names
+}
+
+// Returns a valueType matching values by their name,
+// values of basic kinds are also parsed by their kind.
+// Only the valid values of the enum are accepted.
+func newEnumValueType(tipe reflect.Type) *valueType {
+	names := enumNames(tipe)
+	return &valueType{
+		metavar: strings.Join(names, choicesDelimiter),
+		parse: func(p *parameter, value string) (reflect.Value, error) {
+			values := enumValues(tipe)
+			for i, name := range names {
+				if strings.EqualFold(name, value) {
+					return values[i], nil
+				}
+			}
+			if isBasicKind(tipe) {
+				parsed, err := parseKind(tipe, value)
+				if err == nil {
+					for _, enumValue := range values {
+						if enumValue.Interface() == parsed.Interface() {
+							return parsed, nil
+						}
+					}
+				}
+			}
+			return reflect.Value{}, fmt.Errorf(
+				"expected one of %s", strings.Join(names, ", "),
+			)
+		},
+		format: formatSprint,
+	}
+}
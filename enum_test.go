@@ -0,0 +1,81 @@
+package yagclif
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Enum type matched by the names of its values.
+type enumLevel int
+
+const (
+	enumDebug enumLevel = iota
+	enumInfo
+	enumWarn
+)
+
+func (l enumLevel) String() string {
+	return [...]string{"debug", "info", "warn"}[l]
+}
+
+func (l enumLevel) Values() []enumLevel {
+	return []enumLevel{enumDebug, enumInfo, enumWarn}
+}
+
+// Stringer without list of values.
+type stringerOnly int
+
+func (s stringerOnly) String() string {
+	return "stringer"
+}
+
+func TestIsEnumType(t *testing.T) {
+	assert.True(t, isEnumType(reflect.TypeOf(enumDebug)))
+	assert.False(t, isEnumType(reflect.TypeOf(stringerOnly(0))))
+	assert.False(t, isEnumType(reflect.TypeOf(0)))
+}
+
+func TestEnumNames(t *testing.T) {
+	assert.Equal(t, []string{"debug", "info", "warn"}, enumNames(reflect.TypeOf(enumDebug)))
+}
+
+func TestEnumParameters(t *testing.T) {
+	type foo struct {
+		Level  enumLevel   `yagclif:"default:info"`
+		Levels []enumLevel `yagclif:"delimiter:,"`
+		Other  stringerOnly
+	}
+	fooType := reflect.TypeOf(foo{})
+	t.Run("works", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		_, err = params.ParseArguments(fooVar, []string{"--levels", "WARN,0", "--other", "42"})
+		assert.Nil(t, err)
+		assert.Equal(t, &foo{
+			Level:  enumInfo,
+			Levels: []enumLevel{enumWarn, enumDebug},
+			Other:  42,
+		}, fooVar)
+	})
+	t.Run("returns error with names", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"--level", "trace"},
+			{"--level", "3"},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			_, err = params.ParseArguments(&foo{}, args)
+			assert.NotNil(t, err, args)
+			assert.Contains(t, err.Error(), "debug, info, warn", args)
+		}
+	})
+	t.Run("help shows names", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		assert.Contains(t, params[0].GetHelp(), "--level debug|info|warn (default=info)")
+		assert.Contains(t, params[1].GetHelp(), "--levels []debug|info|warn")
+	})
+}
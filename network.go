@@ -0,0 +1,97 @@
+package yagclif
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+)
+
+// HostPort is a network address made of a host
+// and a port, written as host:port in the cli.
+type HostPort struct {
+	Host string
+	Port uint16
+}
+
+// String returns the address as host:port.
+func (hp HostPort) String() string {
+	return net.JoinHostPort(hp.Host, strconv.Itoa(int(hp.Port)))
+}
+
+// Parses a host:port address and checks the port syntax and range.
+func parseHostPort(value string) (interface{}, error) {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil {
+		return nil, err
+	}
+	if port == "" {
+		return nil, fmt.Errorf("missing port in %s", value)
+	}
+	portNumber, err := strconv.ParseUint(port, 10, 16)
+	if numError, ok := err.(*strconv.NumError); ok && numError.Err == strconv.ErrRange {
+		return nil, fmt.Errorf("port %s is not in range 0-65535", port)
+	} else if err != nil {
+		return nil, fmt.Errorf("port %s is not a number", port)
+	}
+	return HostPort{Host: host, Port: uint16(portNumber)}, nil
+}
+
+// Parses an ip address.
+func parseIP(value string) (interface{}, error) {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, fmt.Errorf("%s is not an ip address", value)
+	}
+	return ip, nil
+}
+
+// Parses a network in CIDR notation.
+func parseIPNet(value string) (interface{}, error) {
+	_, ipNet, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a CIDR network", value)
+	}
+	return ipNet, nil
+}
+
+// Parses an absolute url.
+func parseURL(value string) (interface{}, error) {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Scheme == "" {
+		return nil, fmt.Errorf("%s is not an absolute url", value)
+	}
+	return parsed, nil
+}
+
+func init() {
+	dereference := func(parse ParseFunc) ParseFunc {
+		return func(value string) (interface{}, error) {
+			parsed, err := parse(value)
+			if err != nil {
+				return nil, err
+			}
+			return reflect.ValueOf(parsed).Elem().Interface(), nil
+		}
+	}
+	networkTypes := []struct {
+		value   interface{}
+		metavar string
+		parse   ParseFunc
+	}{
+		{net.IP{}, "ip", parseIP},
+		{&net.IPNet{}, "cidr", parseIPNet},
+		{net.IPNet{}, "cidr", dereference(parseIPNet)},
+		{&url.URL{}, "url", parseURL},
+		{url.URL{}, "url", dereference(parseURL)},
+		{HostPort{}, "host:port", parseHostPort},
+	}
+	for _, networkType := range networkTypes {
+		tipe := reflect.TypeOf(networkType.value)
+		registeredTypes[tipe] = newValueType(tipe, networkType.metavar, networkType.parse, nil)
+	}
+}
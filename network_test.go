@@ -0,0 +1,103 @@
+package yagclif
+
+import (
+	"net"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHostPort(t *testing.T) {
+	t.Run("works", func(t *testing.T) {
+		hostPort, err := parseHostPort("localhost:8080")
+		assert.Nil(t, err)
+		assert.Equal(t, HostPort{Host: "localhost", Port: 8080}, hostPort)
+		hostPort, err = parseHostPort("[::1]:0")
+		assert.Nil(t, err)
+		assert.Equal(t, "[::1]:0", hostPort.(HostPort).String())
+	})
+	t.Run("returns errors", func(t *testing.T) {
+		_, err := parseHostPort("localhost")
+		assert.NotNil(t, err)
+		_, err = parseHostPort("localhost:65536")
+		assert.EqualError(t, err, "port 65536 is not in range 0-65535")
+		_, err = parseHostPort("localhost:http")
+		assert.EqualError(t, err, "port http is not a number")
+		_, err = parseHostPort("localhost:")
+		assert.EqualError(t, err, "missing port in localhost:")
+		_, err = parseHostPort("localhost:-1")
+		assert.EqualError(t, err, "port -1 is not a number")
+	})
+}
+
+func TestNetworkParameters(t *testing.T) {
+	type foo struct {
+		Listen   HostPort
+		Peer     net.IP
+		Subnet   *net.IPNet
+		Network  net.IPNet
+		Endpoint *url.URL
+		Backups  []HostPort `yagclif:"delimiter:,"`
+	}
+	fooType := reflect.TypeOf(foo{})
+	t.Run("works", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		_, err = params.ParseArguments(fooVar, []string{
+			"--listen", ":8080", "--peer", "10.0.0.1",
+			"--subnet", "10.0.0.0/8", "--network", "192.168.1.0/24",
+			"--endpoint", "https://example.com/api",
+			"--backups", "a:1,b:2",
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, HostPort{Port: 8080}, fooVar.Listen)
+		assert.Equal(t, "10.0.0.1", fooVar.Peer.String())
+		assert.Equal(t, "10.0.0.0/8", fooVar.Subnet.String())
+		assert.Equal(t, "192.168.1.0/24", fooVar.Network.String())
+		assert.Equal(t, "example.com", fooVar.Endpoint.Host)
+		assert.Equal(t, []HostPort{{"a", 1}, {"b", 2}}, fooVar.Backups)
+	})
+	t.Run("stays nil when absent", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		_, err = params.ParseArguments(fooVar, []string{})
+		assert.Nil(t, err)
+		assert.Nil(t, fooVar.Subnet)
+		assert.Nil(t, fooVar.Endpoint)
+	})
+	t.Run("errors name the flag", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"--listen", "localhost:99999"},
+			{"--peer", "10.0.0"},
+			{"--subnet", "10.0.0.1"},
+			{"--endpoint", "example.com"},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			_, err = params.ParseArguments(&foo{}, args)
+			assert.NotNil(t, err, args)
+			assert.Contains(t, err.Error(), args[0], args)
+		}
+	})
+	t.Run("help shows metavars", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		assert.Contains(t, params[0].GetHelp(), "--listen host:port")
+		assert.Contains(t, params[1].GetHelp(), "--peer ip")
+		assert.Contains(t, params[2].GetHelp(), "--subnet cidr")
+		assert.Contains(t, params[4].GetHelp(), "--endpoint url")
+		assert.Contains(t, params[5].GetHelp(), "--backups []host:port")
+	})
+	t.Run("help formats defaults", func(t *testing.T) {
+		type bar struct {
+			Network net.IPNet `yagclif:"default:10.0.0.1/8"`
+		}
+		params, err := newParameters(reflect.TypeOf(bar{}))
+		assert.Nil(t, err)
+		assert.Contains(t, params[0].GetHelp(), "default=10.0.0.0/8")
+	})
+}
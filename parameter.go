@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Name of the tag to parse.
@@ -15,6 +18,14 @@ const tagName = "yagclif"
 // Value to prefix to name value.
 const namePrefix = "--"
 
+// Value between a cli name and
+// its inline value : --name=value.
+const inlineValueDelimiter = "="
+
+// Value to prefix to name value
+// to set a boolean to false.
+const negationPrefix = "no-"
+
 // Value to prefix to shortName value.
 const shortNamePrefix = "-"
 
@@ -25,26 +36,106 @@ const constraintValueDelimiter = ":"
 // Value of the delimiter between constraints.
 const constraintsDelimiter = ";"
 
+// Value enclosing a constraint value containing
+// delimiters, doubled to be used inside the value.
+const quote = "'"
+
+// Value of the delimiter between choices.
+const choicesDelimiter = "|"
+
+// Value of the delimiter between the items of
+// list constraints such as requires and alias.
+const listDelimiter = "|"
+
+// Default value of the delimiter between
+// keys and values of map types.
+const kvDelimiter = "="
+
+// Layouts that can be referenced by name
+// in the layout constraint.
+var namedLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"Stamp":       time.Stamp,
+	"DateTime":    "2006-01-02 15:04:05",
+	"DateOnly":    "2006-01-02",
+	"TimeOnly":    "15:04:05",
+}
+
 // Struct for stroring key-value string pair
 type keyValuePair struct {
 	key   string
 	value string
 }
 
-// Split a constraint as key-value constraint
+// Splits a tag into constraints, delimiters in values
+// enclosed in quotes are kept : pattern:'^[a-z;:]+$'.
+func splitConstraints(tag string) ([]string, error) {
+	constraints := []string{}
+	quoted, start := false, 0
+	for i := 0; i < len(tag); i++ {
+		rest := tag[i:]
+		switch {
+		case quoted && strings.HasPrefix(rest, quote+quote):
+			i += len(quote+quote) - 1
+		case quoted && strings.HasPrefix(rest, quote):
+			quoted = false
+		case !quoted && strings.HasPrefix(rest, constraintValueDelimiter+quote) &&
+			!strings.Contains(tag[start:i], constraintValueDelimiter):
+			quoted = true
+			i += len(constraintValueDelimiter+quote) - 1
+		case !quoted && strings.HasPrefix(rest, constraintsDelimiter):
+			constraints = append(constraints, tag[start:i])
+			start = i + len(constraintsDelimiter)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("syntax error unterminated %s in %s", quote, tag)
+	}
+	return append(constraints, tag[start:]), nil
+}
+
+// Removes the quotes around a value,
+// doubled quotes are replaced by a quote.
+func unquote(value string) (string, error) {
+	inner := strings.TrimSuffix(strings.TrimPrefix(value, quote), quote)
+	if len(value) < 2*len(quote) || len(inner) != len(value)-2*len(quote) ||
+		strings.Contains(strings.ReplaceAll(inner, quote+quote, ""), quote) {
+		return "", fmt.Errorf("syntax error invalid quoted value %s", value)
+	}
+	return strings.ReplaceAll(inner, quote+quote, quote), nil
+}
+
+// Split a constraint as key-value constraint,
+// quoted values can contain any character.
 func splitConstraint(constraint string) (keyValuePair, error) {
-	parts := strings.Split(constraint, constraintValueDelimiter)
-	switch len(parts) {
-	case 1:
+	parts := strings.SplitN(constraint, constraintValueDelimiter, 2)
+	if len(parts) == 1 {
 		return keyValuePair{
 			parts[0], "",
 		}, nil
-	case 2:
+	}
+	if strings.HasPrefix(parts[1], quote) {
+		value, err := unquote(parts[1])
 		return keyValuePair{
-			parts[0], parts[1],
-		}, nil
+			parts[0], value,
+		}, err
+	}
+	if strings.Contains(parts[1], constraintValueDelimiter) {
+		return keyValuePair{}, fmt.Errorf("syntax error too many characters %s ", constraintValueDelimiter)
 	}
-	return keyValuePair{}, fmt.Errorf("syntax error too many characters %s ", constraintValueDelimiter)
+	return keyValuePair{
+		parts[0], parts[1],
+	}, nil
 }
 
 // Struct defining a parameter from a structField.
@@ -68,29 +159,113 @@ type parameter struct {
 	// If true not finding this parameter
 	// will result is an error.
 	used bool
-	// Value used to parse array types.
+	// Value used to parse array and map types.
 	delimiter string
-	// Type of the parameter only types
-	// bool,int,string,[]int,[]string are supported.
+	// Value between keys and values of map types.
+	kvDelimiter string
+	// Type of the parameter only registered types,
+	// their slices and pointers to them are supported.
 	tipe reflect.Type
 	// Default Value
 	defaultValue string
 	// Default ENV key
 	envKey string
+	// Layout used to parse time types.
+	layout string
+	// If true integer values are parsed
+	// as human-readable byte sizes.
+	byteSize bool
+	// If true the integer parameter counts
+	// how many times it was used.
+	counter bool
+	// If true the slice parameter can be used
+	// multiple times, values are appended.
+	accumulate bool
+	// Values allowed for the parameter
+	// or each of its elements.
+	choices []string
+	// Parsed values of the choices.
+	choiceValues []reflect.Value
+	// If true the parameter is bound to a non-flag
+	// argument by its position instead of a cli name.
+	positional bool
+	// Position of the non-flag argument
+	// bound to a positional parameter.
+	position int
+	// If true the slice parameter collects the
+	// non-flag arguments left after the positionals.
+	rest bool
+	// Bounds of numeric values or of the length
+	// of strings, slices and maps.
+	min string
+	max string
+	// Parsed bounds, invalid if not set.
+	minValue reflect.Value
+	maxValue reflect.Value
+	// Expression string values or each
+	// of their elements must match.
+	pattern *regexp.Regexp
+	// Message displayed instead of the
+	// pattern when a value does not match.
+	patternHint string
+	// Name of the group of parameters
+	// the parameter belongs to.
+	group string
+	// If true only one parameter of
+	// the group can be used.
+	exclusive bool
+	// If true at least one parameter
+	// of the group must be set.
+	atLeastOne bool
+	// Names of the struct fields that must
+	// be set when the parameter is used.
+	requires []string
+	// Parameters resolved from the requires names.
+	requiredParams parameters
+	// Struct field and value making
+	// the parameter mandatory.
+	requiredIf keyValuePair
+	// Parameter and parsed value
+	// resolved from requiredIf.
+	requiredIfParam *parameter
+	requiredIfValue reflect.Value
+	// Hint printed when a deprecated parameter
+	// is used, empty if not deprecated.
+	deprecated string
+	// Long names matching the parameter
+	// in addition to its name.
+	aliases []string
 }
 
 // Returns Cli names (text before the parameter)
-// as lowercase strings.
+// as lowercase strings : the name, the shortname
+// and the aliases. Positional parameters are named <name>.
 func (p *parameter) CliNames() []string {
-	if p.hasShortName() {
-		return []string{
-			fmt.Sprint(namePrefix, strings.ToLower(p.name)),
-			fmt.Sprint(shortNamePrefix, strings.ToLower(p.shortName)),
-		}
+	if p.positional {
+		return []string{fmt.Sprint("<", strings.ToLower(p.name), ">")}
+	} else if p.rest {
+		return []string{fmt.Sprint("<", strings.ToLower(p.name), ">...")}
 	}
-	return []string{
+	names := []string{
 		fmt.Sprint(namePrefix, strings.ToLower(p.name)),
 	}
+	if p.hasShortName() {
+		names = append(names, fmt.Sprint(shortNamePrefix, strings.ToLower(p.shortName)))
+	}
+	for _, alias := range p.aliases {
+		names = append(names, fmt.Sprint(namePrefix, strings.ToLower(alias)))
+	}
+	return names
+}
+
+// Returns the names and the aliases
+// as lowercase strings without prefix.
+func (p *parameter) longNames() []string {
+	names := []string{strings.ToLower(p.name)}
+	for _, alias := range p.aliases {
+		names = append(names, strings.ToLower(alias))
+	}
+	return names
 }
 
 // Splits a string by the delimiter.
@@ -98,14 +273,255 @@ func (p *parameter) Split(s string) []string {
 	return strings.Split(s, p.delimiter)
 }
 
+// Returns the layout used to parse time values.
+func (p *parameter) getLayout() string {
+	if p.layout == "" {
+		return time.RFC3339
+	}
+	if layout, exists := namedLayouts[p.layout]; exists {
+		return layout
+	}
+	return p.layout
+}
+
+// Returns the type of the scalar values of the parameter,
+// the element type for array types.
+func (p *parameter) scalarType() reflect.Type {
+	if p.IsArrayType() {
+		return p.baseType().Elem()
+	}
+	return p.baseType()
+}
+
+// Returns if the parameter is a boolean flag
+// set without value.
+func (p *parameter) isBool() bool {
+	t := p.baseType()
+	return t != nil && t.Kind() == reflect.Bool
+}
+
+// Returns the cli names setting a boolean to false.
+func (p *parameter) negatedNames() []string {
+	names := []string{}
+	for _, name := range p.longNames() {
+		names = append(names, fmt.Sprint(namePrefix, negationPrefix, name))
+	}
+	return names
+}
+
+// Returns if the string is a negated name of a boolean.
+func (p *parameter) MatchesNegated(s string) bool {
+	if !p.isBool() {
+		return false
+	}
+	for _, name := range p.negatedNames() {
+		if name == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns if the parameter is bound to non-flag
+// arguments instead of a cli name.
+func (p *parameter) isPositional() bool {
+	return p.positional || p.rest
+}
+
+// Returns if the boolean is true, false for nil pointers.
+func (p *parameter) isTrue(obj interface{}) bool {
+	value := p.getValue(obj)
+	if p.isPointer() {
+		return !value.IsNil() && value.Elem().Bool()
+	}
+	return value.Bool()
+}
+
+// Returns if the parameter was used by the cli,
+// booleans turned off by --no-name or --name=false are not.
+func (p *parameter) isUsedOn(obj interface{}) bool {
+	return p.used && (!p.isBool() || p.isTrue(obj))
+}
+
+// Returns if a value was given by the cli, the env
+// or the default value, booleans are set when true.
+func (p *parameter) isSet(obj interface{}) bool {
+	if p.isBool() {
+		return p.isTrue(obj)
+	}
+	return p.used || p.defaultValue != "" ||
+		(p.envKey != "" && os.Getenv(p.envKey) != "")
+}
+
+// Returns if the parameter is a time or a slice of times.
+func (p *parameter) isTimeType() bool {
+	return p.scalarType() == timeType
+}
+
+// Returns if the parameter is an integer
+// or a slice of integers.
+func (p *parameter) isByteSizeType() bool {
+	t := p.scalarType()
+	return t != nil && isIntegerKind(t)
+}
+
+// Returns if the value is one of the choices.
+func (p *parameter) checkChoices(parsed reflect.Value, value string) error {
+	if len(p.choiceValues) == 0 {
+		return nil
+	}
+	for _, choice := range p.choiceValues {
+		if reflect.DeepEqual(choice.Interface(), parsed.Interface()) {
+			return nil
+		}
+	}
+	return fmt.Errorf(
+		"invalid value %s for %s : allowed values are %s",
+		value, p.CliNames()[0], strings.Join(p.choices, ", "),
+	)
+}
+
+// Returns if the value matches the pattern.
+func (p *parameter) checkPattern(value string) error {
+	if p.pattern == nil || p.pattern.MatchString(value) {
+		return nil
+	}
+	hint := p.patternHint
+	if hint == "" {
+		hint = fmt.Sprint("must match ", p.pattern)
+	}
+	return fmt.Errorf("invalid value %s for %s : %s", value, p.CliNames()[0], hint)
+}
+
+// Returns if the value is one of the choices
+// and matches the pattern.
+func (p *parameter) checkValue(parsed reflect.Value, value string) error {
+	if err := p.checkChoices(parsed, value); err != nil {
+		return err
+	}
+	return p.checkPattern(value)
+}
+
+// Returns if the bounds apply to the length of the value
+// instead of the value : strings, slices and maps.
+func (p *parameter) isLengthRange() bool {
+	return p.IsArrayType() || p.IsMapType() || p.baseType().Kind() == reflect.String
+}
+
+// Returns if the bounds apply to the numeric value.
+func (p *parameter) isNumericRange() bool {
+	switch p.baseType().Kind() {
+	case reflect.Float32, reflect.Float64:
+		return true
+	}
+	return isIntegerKind(p.baseType())
+}
+
+// Parses the bounds so that values are compared
+// with values of the same type.
+func (p *parameter) parseRange() (err error) {
+	parseBound := func(bound string) (reflect.Value, error) {
+		if bound == "" {
+			return reflect.Value{}, nil
+		} else if p.isLengthRange() {
+			length, err := strconv.Atoi(bound)
+			if err != nil || length < 0 {
+				return reflect.Value{}, fmt.Errorf("invalid length %s", bound)
+			}
+			return reflect.ValueOf(length), nil
+		}
+		return p.parseScalar(p.baseType(), bound)
+	}
+	if p.minValue, err = parseBound(p.min); err != nil {
+		return err
+	}
+	if p.maxValue, err = parseBound(p.max); err != nil {
+		return err
+	}
+	if p.minValue.IsValid() && p.maxValue.IsValid() && compareNumbers(p.minValue, p.maxValue) > 0 {
+		return fmt.Errorf("min %s is greater than max %s", p.min, p.max)
+	}
+	return nil
+}
+
+// Returns the allowed range as min..max,
+// a missing bound is left empty.
+func (p *parameter) formatRange() string {
+	return fmt.Sprint(p.min, "..", p.max)
+}
+
+// Returns if the value is within the bounds.
+// Nil pointers are not checked.
+func (p *parameter) checkRange(value reflect.Value) error {
+	if !p.minValue.IsValid() && !p.maxValue.IsValid() {
+		return nil
+	}
+	if p.isPointer() {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	measured := value
+	if p.isLengthRange() {
+		measured = reflect.ValueOf(value.Len())
+	}
+	if (!p.minValue.IsValid() || compareNumbers(measured, p.minValue) >= 0) &&
+		(!p.maxValue.IsValid() || compareNumbers(measured, p.maxValue) <= 0) {
+		return nil
+	}
+	if p.isLengthRange() {
+		return fmt.Errorf(
+			"invalid length %d for %s : allowed range is %s",
+			value.Len(), p.CliNames()[0], p.formatRange(),
+		)
+	}
+	return fmt.Errorf(
+		"invalid value %s for %s : allowed range is %s",
+		p.lookupType(value.Type()).format(p, value), p.CliNames()[0], p.formatRange(),
+	)
+}
+
+// Compares two numbers of the same kind,
+// returns -1, 0 or 1 as a is lower, equal or greater than b.
+func compareNumbers(a, b reflect.Value) int {
+	switch a.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if a.Uint() < b.Uint() {
+			return -1
+		} else if a.Uint() > b.Uint() {
+			return 1
+		}
+	case reflect.Float32, reflect.Float64:
+		if a.Float() < b.Float() {
+			return -1
+		} else if a.Float() > b.Float() {
+			return 1
+		}
+	default:
+		if a.Int() < b.Int() {
+			return -1
+		} else if a.Int() > b.Int() {
+			return 1
+		}
+	}
+	return 0
+}
+
 // Returns the help of a parameter.
 func (p *parameter) GetHelp() string {
 	var buffer bytes.Buffer
-	buffer.WriteString(strings.Join(p.CliNames(), " "))
+	cliNames := p.CliNames()
+	for i, name := range cliNames {
+		if p.isBool() && strings.HasPrefix(name, namePrefix) {
+			cliNames[i] = fmt.Sprint(namePrefix, "[", negationPrefix, "]", strings.TrimPrefix(name, namePrefix))
+		}
+	}
+	buffer.WriteString(strings.Join(cliNames, " "))
 	buffer.WriteString(" ")
-	buffer.WriteString(p.tipe.String())
+	buffer.WriteString(metavarOf(p.tipe, p.lookupType))
 	buffer.WriteString(" ")
-	if p.IsArrayType() {
+	if p.IsArrayType() || p.IsMapType() {
 		buffer.WriteString("delimiter ")
 		if p.delimiter == " " {
 			buffer.WriteString("whitespace ")
@@ -115,8 +531,26 @@ func (p *parameter) GetHelp() string {
 			buffer.WriteString(" ")
 		}
 	}
+	if p.IsMapType() {
+		buffer.WriteString("kvdelimiter ")
+		buffer.WriteString(p.kvDelimiter)
+		buffer.WriteString(" ")
+	}
+	if p.positional {
+		buffer.WriteString("position ")
+		buffer.WriteString(strconv.Itoa(p.position))
+		buffer.WriteString(" ")
+	}
+	if p.isTimeType() {
+		buffer.WriteString("layout ")
+		buffer.WriteString(p.getLayout())
+		buffer.WriteString(" ")
+	}
 
-	parenthesis := p.mandatory || p.defaultValue != "" || p.envKey != ""
+	hasRange := p.min != "" || p.max != ""
+	parenthesis := p.mandatory || p.counter || p.accumulate || hasRange || p.pattern != nil ||
+		len(p.requiredParams) > 0 || p.requiredIfParam != nil || p.deprecated != "" ||
+		p.defaultValue != "" || p.envKey != "" || len(p.choices) > 0
 	if parenthesis {
 		buffer.WriteString("(")
 	}
@@ -124,8 +558,36 @@ func (p *parameter) GetHelp() string {
 	if p.mandatory {
 		infos = append(infos, "mandatory")
 	}
+	if p.deprecated != "" {
+		infos = append(infos, fmt.Sprint("deprecated=", p.deprecated))
+	}
+	for _, required := range p.requiredParams {
+		infos = append(infos, fmt.Sprint("requires=", required.CliNames()[0]))
+	}
+	if p.requiredIfParam != nil {
+		v := fmt.Sprint("requiredif=", p.requiredIfParam.CliNames()[0], kvDelimiter, p.requiredIf.value)
+		infos = append(infos, v)
+	}
+	if p.counter {
+		infos = append(infos, "count")
+	}
+	if p.accumulate {
+		infos = append(infos, "repeatable")
+	}
+	if len(p.choices) > 0 {
+		v := fmt.Sprint("choices=", strings.Join(p.choices, choicesDelimiter))
+		infos = append(infos, v)
+	}
+	if p.pattern != nil {
+		infos = append(infos, fmt.Sprint("pattern=", p.pattern))
+	}
+	if hasRange && p.isLengthRange() {
+		infos = append(infos, fmt.Sprint("length=", p.formatRange()))
+	} else if hasRange {
+		infos = append(infos, fmt.Sprint("range=", p.formatRange()))
+	}
 	if p.defaultValue != "" {
-		v := fmt.Sprint("default=", p.defaultValue)
+		v := fmt.Sprint("default=", p.formatDefault())
 		infos = append(infos, v)
 	}
 	if p.envKey != "" {
@@ -144,13 +606,59 @@ func (p *parameter) GetHelp() string {
 	return buffer.String()
 }
 
+// Returns the default value as displayed in the help
+// using the format of the registered type.
+func (p *parameter) formatDefault() string {
+	value := reflect.New(p.tipe).Elem()
+	setter := p.setterOnValue(value)
+	if setter == nil || setter(p.defaultValue) != nil {
+		return p.defaultValue
+	}
+	if p.isPointer() {
+		value = value.Elem()
+	}
+	if p.IsMapType() {
+		return p.formatMap(value)
+	}
+	if !p.IsArrayType() {
+		return p.lookupType(p.baseType()).format(p, value)
+	}
+	elemType := p.lookupType(p.baseType().Elem())
+	parts := make([]string, value.Len())
+	for i := range parts {
+		parts[i] = elemType.format(p, value.Index(i))
+	}
+	return strings.Join(parts, p.delimiter)
+}
+
+// Returns the entries of a map as key-value pairs
+// sorted by key and joined by the delimiter.
+func (p *parameter) formatMap(value reflect.Value) string {
+	keyType, elemType := p.lookupType(p.tipe.Key()), p.lookupType(p.tipe.Elem())
+	entries := make([]string, 0, value.Len())
+	iter := value.MapRange()
+	for iter.Next() {
+		entries = append(entries, fmt.Sprint(
+			keyType.format(p, iter.Key()),
+			p.kvDelimiter,
+			elemType.format(p, iter.Value()),
+		))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, p.delimiter)
+}
+
 // Returns if a shortName has been defined.
 func (p *parameter) hasShortName() bool {
 	return p.shortName != ""
 }
 
-// Returns if the parameter matches the string.
+// Returns if the parameter matches the string,
+// positional parameters never match.
 func (p *parameter) Matches(s string) bool {
+	if p.isPositional() {
+		return false
+	}
 	for _, name := range p.CliNames() {
 		if name == s {
 			return true
@@ -159,10 +667,31 @@ func (p *parameter) Matches(s string) bool {
 	return false
 }
 
+// Returns if the parameter is a slice or a fixed-size
+// array of a supported scalar type.
 func (p *parameter) IsArrayType() bool {
-	stringArrayType, intArrayType := reflect.TypeOf([]string{}), reflect.TypeOf([]int{})
-	t := p.tipe
-	return t == stringArrayType || t == intArrayType
+	t := p.baseType()
+	return t != nil && isArrayType(t)
+}
+
+// Returns if the parameter is a map of supported scalar types.
+func (p *parameter) IsMapType() bool {
+	return p.tipe != nil && isMapType(p.tipe)
+}
+
+// Returns if the parameter is a pointer allocated
+// only when a value is provided.
+func (p *parameter) isPointer() bool {
+	return p.tipe != nil && isPointerType(p.tipe)
+}
+
+// Returns the type values are parsed as,
+// the pointed type for pointer parameters.
+func (p *parameter) baseType() reflect.Type {
+	if p.isPointer() {
+		return p.tipe.Elem()
+	}
+	return p.tipe
 }
 
 // Gets value of the object by reflect
@@ -175,82 +704,235 @@ func (p *parameter) getValue(obj interface{}) reflect.Value {
 	return fieldValue
 }
 
-// Sets
-func (p *parameter) setBool(target reflect.Value) func(value string) error {
-	target.SetBool(true)
-	return nil
+// Returns how values of the type are parsed
+// taking the parameter constraints into account.
+func (p *parameter) lookupType(tipe reflect.Type) *valueType {
+	if p.byteSize && isIntegerKind(tipe) {
+		return newByteSizeValueType(tipe)
+	}
+	return lookupType(tipe)
 }
 
-func (p *parameter) setInt(target reflect.Value) func(value string) error {
+// Parses a scalar value of type tipe
+// using the registered type.
+func (p *parameter) parseScalar(tipe reflect.Type, value string) (reflect.Value, error) {
+	valueType := p.lookupType(tipe)
+	if valueType == nil {
+		return reflect.Value{}, fmt.Errorf("Incompatible type %s", tipe)
+	}
+	parsed, err := valueType.parse(p, value)
+	if err != nil {
+		return parsed, fmt.Errorf(
+			"invalid value %s for %s : %s",
+			value, p.CliNames()[0], err,
+		)
+	}
+	return parsed, nil
+}
+
+func (p *parameter) setScalar(target reflect.Value) func(value string) error {
 	return func(value string) error {
-		intValue, err := strconv.Atoi(value)
+		parsed, err := p.parseScalar(p.baseType(), value)
 		if err != nil {
 			return err
 		}
-		target.SetInt(int64(intValue))
+		if err := p.checkValue(parsed, value); err != nil {
+			return err
+		}
+		target.Set(parsed)
 		return nil
 	}
 }
-func (p *parameter) setString(target reflect.Value) func(value string) error {
+
+func (p *parameter) setArray(target reflect.Value) func(value string) error {
 	return func(value string) error {
-		target.SetString(value)
+		parts, tipe := p.Split(value), p.baseType()
+		var array reflect.Value
+		if tipe.Kind() == reflect.Array {
+			if len(parts) != tipe.Len() {
+				return fmt.Errorf(
+					"%s expects %d values but found %d",
+					p.CliNames()[0], tipe.Len(), len(parts),
+				)
+			}
+			array = reflect.New(tipe).Elem()
+		} else {
+			array = reflect.MakeSlice(tipe, len(parts), len(parts))
+		}
+		for i, part := range parts {
+			parsed, err := p.parseScalar(tipe.Elem(), part)
+			if err != nil {
+				return err
+			}
+			if err := p.checkValue(parsed, part); err != nil {
+				return err
+			}
+			array.Index(i).Set(parsed)
+		}
+		if p.accumulate {
+			array = reflect.AppendSlice(target, array)
+		}
+		target.Set(array)
 		return nil
 	}
 }
-func (p *parameter) setStringArray(target reflect.Value) func(value string) error {
+
+// Allocates the pointer once the pointed value is set.
+func (p *parameter) setPointer(target reflect.Value) func(value string) error {
+	pointed := reflect.New(p.baseType())
+	setter := p.setterOnBaseValue(pointed.Elem())
+	if setter == nil {
+		return nil
+	}
 	return func(value string) error {
-		parts := p.Split(value)
-		target.Set(reflect.ValueOf(parts))
+		if err := setter(value); err != nil {
+			return err
+		}
+		target.Set(pointed)
 		return nil
 	}
 }
-func (p *parameter) setIntArray(target reflect.Value) func(value string) error {
+
+// Adds the key-value pairs to the map,
+// the map is allocated if nil.
+func (p *parameter) setMap(target reflect.Value) func(value string) error {
 	return func(value string) error {
-		parts := p.Split(value)
-		intParts := []int{}
-		for _, i := range parts {
-			j, err := strconv.Atoi(i)
+		keys, values := []reflect.Value{}, []reflect.Value{}
+		for _, entry := range p.Split(value) {
+			parts := strings.SplitN(entry, p.kvDelimiter, 2)
+			if len(parts) != 2 {
+				return fmt.Errorf(
+					"expected key%svalue for %s but found %s",
+					p.kvDelimiter, p.CliNames()[0], entry,
+				)
+			}
+			key, err := p.parseScalar(p.tipe.Key(), parts[0])
 			if err != nil {
 				return err
 			}
-			intParts = append(intParts, j)
+			elem, err := p.parseScalar(p.tipe.Elem(), parts[1])
+			if err != nil {
+				return err
+			}
+			keys, values = append(keys, key), append(values, elem)
+		}
+		if target.IsNil() {
+			target.Set(reflect.MakeMap(p.tipe))
+		}
+		for i, key := range keys {
+			target.SetMapIndex(key, values[i])
 		}
-		target.Set(reflect.ValueOf(intParts))
 		return nil
 	}
 }
 
-func (p *parameter) setterOnValue(target reflect.Value) func(value string) error {
-	switch p.tipe {
-	case reflect.TypeOf(true):
-		return p.setBool(target)
-	case reflect.TypeOf(1):
-		return p.setInt(target)
-	case reflect.TypeOf(""):
-		return p.setString(target)
-	case reflect.TypeOf([]string{}):
-		return p.setStringArray(target)
-	case reflect.TypeOf([]int{}):
-		return p.setIntArray(target)
+func (p *parameter) setterOnBaseValue(target reflect.Value) func(value string) error {
+	switch {
+	case p.IsMapType():
+		return p.setMap(target)
+	case p.IsArrayType():
+		return p.setArray(target)
+	case isScalarType(p.baseType()):
+		return p.setScalar(target)
 	}
 	return nil
 }
 
+func (p *parameter) setterOnValue(target reflect.Value) func(value string) error {
+	if p.isPointer() {
+		return p.setPointer(target)
+	}
+	return p.setterOnBaseValue(target)
+}
+
+// Increments the counter,
+// no value is expected from the cli.
+func (p *parameter) increment(target reflect.Value) {
+	switch target.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		target.SetUint(target.Uint() + 1)
+	default:
+		target.SetInt(target.Int() + 1)
+	}
+}
+
 // fills an object with the desired value
+// counters are incremented on each usage,
+// map types and accumulated slices can be used
+// multiple times, their entries replace the
+// default ones on first usage.
 func (p *parameter) SetterCallback(obj interface{}) (func(value string) error, error) {
-	if p.used {
+	if p.counter {
+		p.used = true
+		p.increment(p.getValue(obj))
+		return nil, nil
+	}
+	if p.used && !p.IsMapType() && !p.accumulate {
 		return nil, fmt.Errorf("%s used multiple times", p.name)
 	}
-	p.used = true
 	target := p.getValue(obj)
+	if !p.used && (p.IsMapType() || p.accumulate) {
+		target.Set(reflect.Zero(p.tipe))
+	}
+	p.used = true
 	setter := p.setterOnValue(target)
-	// no setter callback for bool type
-	if setter == nil && p.tipe != reflect.TypeOf(true) {
+	if setter == nil {
 		return nil, fmt.Errorf("Incompatible type")
 	}
+	// no setter callback for bool type
+	if p.isBool() {
+		return nil, setter("true")
+	}
 	return setter, nil
 }
 
+// fills an object with a value given inline : --name=value.
+// booleans and counters take the value instead of
+// being set to true or incremented.
+func (p *parameter) SetValue(obj interface{}, value string) error {
+	if !p.isBool() && !p.counter {
+		setter, err := p.SetterCallback(obj)
+		if err != nil {
+			return err
+		}
+		return setter(value)
+	}
+	if p.used && !p.counter {
+		return fmt.Errorf("%s used multiple times", p.name)
+	}
+	p.used = true
+	return p.setterOnValue(p.getValue(obj))(value)
+}
+
+// fills the slice with the non-flag arguments
+// left after the positional parameters.
+func (p *parameter) SetRest(obj interface{}, values []string) error {
+	rest := reflect.MakeSlice(p.tipe, 0, len(values))
+	for _, value := range values {
+		parsed, err := p.parseScalar(p.tipe.Elem(), value)
+		if err != nil {
+			return err
+		}
+		if err := p.checkValue(parsed, value); err != nil {
+			return err
+		}
+		rest = reflect.Append(rest, parsed)
+	}
+	p.used = true
+	p.getValue(obj).Set(rest)
+	return nil
+}
+
+// Sets the boolean to false,
+// no value is expected from the cli.
+func (p *parameter) Negate(obj interface{}) error {
+	if p.used {
+		return fmt.Errorf("%s used multiple times", p.name)
+	}
+	p.used = true
+	return p.setterOnValue(p.getValue(obj))("false")
+}
+
 func (p *parameter) setDefault(value reflect.Value) error {
 	exists, err := p.setDefaultFromEnv(value)
 	if exists && err != nil {
@@ -260,40 +942,32 @@ func (p *parameter) setDefault(value reflect.Value) error {
 	}
 	if p.defaultValue != "" {
 		setter := p.setterOnValue(value)
-		return setter(p.defaultValue)
+		if err := setter(p.defaultValue); err != nil {
+			return err
+		}
+		return p.checkRange(value)
 	}
 	return nil
 }
 
 func (p *parameter) setDefaultFromEnv(value reflect.Value) (exists bool, err error) {
-	setter := p.setterOnValue(value)
 	envValue := os.Getenv(p.envKey)
-	if envValue != "" {
-		err := setter(envValue)
-		return true, err
+	if p.envKey != "" && envValue != "" {
+		setter := p.setterOnValue(value)
+		if err := setter(envValue); err != nil {
+			return true, err
+		}
+		return true, p.checkRange(value)
 	}
 	return false, nil
 }
 
 func (p *parameter) testDefaultValue() error {
-	setMockValue := func(value interface{}) error {
-		valueType := reflect.TypeOf(value)
-		mockValue := reflect.New(valueType).Elem()
-		return p.setDefault(mockValue)
-	}
-	switch p.tipe {
-	case reflect.TypeOf(false):
-		return setMockValue(false)
-	case reflect.TypeOf(1):
-		return setMockValue(1)
-	case reflect.TypeOf(""):
-		return setMockValue("")
-	case reflect.TypeOf([]string{}):
-		return setMockValue([]string{})
-	case reflect.TypeOf([]int{}):
-		return setMockValue([]int{})
-	}
-	return fmt.Errorf("Incompatible type")
+	if p.tipe == nil || !isSupportedType(p.tipe) {
+		return fmt.Errorf("Incompatible type")
+	}
+	mockValue := reflect.New(p.tipe).Elem()
+	return p.setDefault(mockValue)
 }
 func (p *parameter) validate() error {
 	getError := func(s string) error {
@@ -301,16 +975,74 @@ func (p *parameter) validate() error {
 			p.name, s,
 		)
 	}
-	if (p.mandatory || p.tipe == reflect.TypeOf(true)) && (p.defaultValue != "" || p.envKey != "") {
+	if p.mandatory && (p.defaultValue != "" || p.envKey != "") {
 		return getError("can not be mandatory or have a default value")
-	} else if !p.IsArrayType() && strings.Trim(p.delimiter, " ") != "" {
+	} else if !p.IsArrayType() && !p.IsMapType() && strings.Trim(p.delimiter, " ") != "" {
 		return getError("delimiter on non array type")
-	} else if p.mandatory && p.tipe == reflect.TypeOf(true) {
+	} else if !p.IsMapType() && p.kvDelimiter != "" {
+		return getError("kvdelimiter on non map type")
+	} else if p.IsMapType() && p.kvDelimiter == p.delimiter {
+		return getError("kvdelimiter can not be the delimiter")
+	} else if !p.isTimeType() && p.layout != "" {
+		return getError("layout on non time type")
+	} else if p.byteSize && !p.isByteSizeType() {
+		return getError("bytesize on non integer type")
+	} else if p.mandatory && p.isBool() {
 		return getError("boolean type can not be mandatory")
+	} else if p.counter && (p.isPointer() || !isIntegerKind(p.tipe)) {
+		return getError("count on non integer type")
+	} else if p.accumulate && (p.tipe.Kind() != reflect.Slice || !p.IsArrayType()) {
+		return getError("accumulate on non slice type")
+	} else if len(p.choices) > 0 && (p.IsMapType() || p.isBool()) {
+		return getError("choices on boolean or map type")
+	} else if p.positional && p.rest {
+		return getError("can not be positional and rest")
+	} else if p.isPositional() && (p.hasShortName() || len(p.aliases) > 0 || p.isBool() || p.counter || p.accumulate) {
+		return getError("positional can not be a flag or have a shortname or an alias")
+	} else if p.rest && (p.tipe.Kind() != reflect.Slice || !p.IsArrayType()) {
+		return getError("rest on non slice type")
+	} else if (p.min != "" || p.max != "") && !p.isLengthRange() && !p.isNumericRange() {
+		return getError("min or max on non numeric, string, slice or map type")
+	} else if p.pattern != nil && (p.IsMapType() || p.scalarType().Kind() != reflect.String) {
+		return getError("pattern on non string type")
+	} else if p.patternHint != "" && p.pattern == nil {
+		return getError("patternhint without pattern")
+	} else if p.group != "" && !p.exclusive && !p.atLeastOne {
+		return getError("group without exclusive or atleastone")
+	} else if (p.exclusive || p.atLeastOne) && p.group == "" {
+		return getError("exclusive or atleastone without group")
+	} else if (p.exclusive || p.atLeastOne) && p.mandatory {
+		return getError("group member can not be mandatory")
+	} else if p.requiredIf.key != "" && (p.mandatory || p.defaultValue != "" || p.envKey != "") {
+		return getError("requiredif can not be mandatory or have a default value")
+	} else if p.requiredIf.key != "" && p.isBool() {
+		return getError("boolean type can not be required")
+	} else if p.deprecated != "" && (p.mandatory || p.isPositional()) {
+		return getError("deprecated can not be mandatory or positional")
+	}
+	if err := p.parseChoices(); err != nil {
+		return getError(err.Error())
+	}
+	if err := p.parseRange(); err != nil {
+		return getError(err.Error())
 	}
 	return p.testDefaultValue()
 }
 
+// Parses the choices so that values
+// are compared with parsed choices.
+func (p *parameter) parseChoices() error {
+	p.choiceValues = make([]reflect.Value, 0, len(p.choices))
+	for _, choice := range p.choices {
+		parsed, err := p.parseScalar(p.scalarType(), choice)
+		if err != nil {
+			return err
+		}
+		p.choiceValues = append(p.choiceValues, parsed)
+	}
+	return nil
+}
+
 // Changes the parameter by the value of the constraint.
 func (p *parameter) fillParameter(constraint string) error {
 	splittedConstraint, err := splitConstraint(constraint)
@@ -337,6 +1069,84 @@ func (p *parameter) fillParameter(constraint string) error {
 	case "delimiter":
 		p.delimiter = value
 		return nil
+	case "kvdelimiter":
+		p.kvDelimiter = value
+		return nil
+	case "layout":
+		p.layout = value
+		return nil
+	case "bytesize":
+		p.byteSize = true
+		return nil
+	case "choices":
+		p.choices = strings.Split(value, choicesDelimiter)
+		return nil
+	case "count":
+		p.counter = true
+		return nil
+	case "accumulate":
+		p.accumulate = true
+		return nil
+	case "positional":
+		position, err := strconv.Atoi(value)
+		if err != nil || position < 0 {
+			return fmt.Errorf("invalid position %s", value)
+		}
+		p.positional, p.position = true, position
+		return nil
+	case "rest":
+		p.rest = true
+		return nil
+	case "min":
+		p.min = value
+		return nil
+	case "max":
+		p.max = value
+		return nil
+	case "pattern":
+		pattern, err := regexp.Compile(value)
+		if err != nil {
+			return err
+		}
+		p.pattern = pattern
+		return nil
+	case "patternhint":
+		p.patternHint = value
+		return nil
+	case "group":
+		p.group = value
+		return nil
+	case "exclusive":
+		p.exclusive = true
+		return nil
+	case "alias":
+		for _, alias := range strings.Split(value, listDelimiter) {
+			if alias == "" || strings.HasPrefix(alias, shortNamePrefix) ||
+				strings.Contains(alias, inlineValueDelimiter) {
+				return fmt.Errorf("invalid alias %s", alias)
+			}
+			p.aliases = append(p.aliases, alias)
+		}
+		return nil
+	case "deprecated":
+		if value == "" {
+			return fmt.Errorf("deprecated needs a hint")
+		}
+		p.deprecated = value
+		return nil
+	case "atleastone":
+		p.atLeastOne = true
+		return nil
+	case "requires":
+		p.requires = strings.Split(value, listDelimiter)
+		return nil
+	case "requiredif":
+		parts := strings.SplitN(value, kvDelimiter, 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("expected field%svalue but found %s", kvDelimiter, value)
+		}
+		p.requiredIf = keyValuePair{parts[0], parts[1]}
+		return nil
 	}
 	return fmt.Errorf("unknown key %s", splittedConstraint.value)
 }
@@ -351,13 +1161,19 @@ func newParameter(sf reflect.StructField) (*parameter, error) {
 	if tag == "omit" {
 		return nil, nil
 	}
-	if newParam.IsArrayType() && newParam.delimiter == "" {
+	if (newParam.IsArrayType() || newParam.IsMapType()) && newParam.delimiter == "" {
 		newParam.delimiter = constraintsDelimiter
 	}
+	if newParam.IsMapType() {
+		newParam.kvDelimiter = kvDelimiter
+	}
 	if tag == "" {
 		return &newParam, nil
 	}
-	constraints := strings.Split(tag, constraintsDelimiter)
+	constraints, err := splitConstraints(tag)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing tag of field %s : %s", newParam.name, err)
+	}
 	for _, constraint := range constraints {
 		err := newParam.fillParameter(constraint)
 		if err != nil {
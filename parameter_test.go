@@ -1,9 +1,15 @@
 package yagclif
 
 import (
+	"fmt"
+	"math/big"
+	"net"
+	"os"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -29,6 +35,41 @@ func TestSplitConstraint(t *testing.T) {
 		_, err := splitConstraint("hello:::")
 		assert.NotNil(t, err)
 	})
+	t.Run("quoted value", func(t *testing.T) {
+		for constraint, expected := range map[string]string{
+			"hello:'a:b;c'":  "a:b;c",
+			"hello:''":       "",
+			"hello:'it''s'":  "it's",
+			"hello:don't":    "don't",
+			"hello:'[::1]'":  "[::1]",
+			"hello:'''a'''":  "'a'",
+			"hello:'a'':'''": "a':'",
+		} {
+			kv, err := splitConstraint(constraint)
+			assert.Nil(t, err, constraint)
+			assert.Equal(t, keyValuePair{key: "hello", value: expected}, kv, constraint)
+		}
+		for _, constraint := range []string{"hello:'a", "hello:'a'b'", "hello:'a'b", "hello:'"} {
+			_, err := splitConstraint(constraint)
+			assert.NotNil(t, err, constraint)
+		}
+	})
+}
+
+func TestSplitConstraints(t *testing.T) {
+	for tag, expected := range map[string][]string{
+		"mandatory":                       {"mandatory"},
+		"a:1;b":                           {"a:1", "b"},
+		"pattern:'^[a-z;]+:[0-9]+$';b:2":  {"pattern:'^[a-z;]+:[0-9]+$'", "b:2"},
+		"description:don't;default:'x;y'": {"description:don't", "default:'x;y'"},
+		"a:'it''s;';b":                    {"a:'it''s;'", "b"},
+	} {
+		constraints, err := splitConstraints(tag)
+		assert.Nil(t, err, tag)
+		assert.Equal(t, expected, constraints, tag)
+	}
+	_, err := splitConstraints("a:'x;b")
+	assert.NotNil(t, err)
 }
 
 func TestSplit(t *testing.T) {
@@ -192,6 +233,261 @@ func TestSetterCallBacks(t *testing.T) {
 		assert.NotNil(t, err)
 	})
 }
+func TestNumericSetters(t *testing.T) {
+	type foo struct {
+		A float64
+		B int8
+		C uint16
+		D int64
+		E []float32 `yagclif:"delimiter:,"`
+		F []uint    `yagclif:"delimiter:,"`
+	}
+	setField := func(index int, value string) (*foo, error) {
+		field := reflect.TypeOf(foo{}).Field(index)
+		param, err := newParameter(field)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		callBack, err := param.SetterCallback(fooVar)
+		assert.Nil(t, err)
+		return fooVar, callBack(value)
+	}
+	t.Run("works", func(t *testing.T) {
+		fooVar, err := setField(0, "4.2")
+		assert.Nil(t, err)
+		assert.Equal(t, 4.2, fooVar.A)
+		fooVar, err = setField(1, "-12")
+		assert.Nil(t, err)
+		assert.Equal(t, int8(-12), fooVar.B)
+		fooVar, err = setField(2, "65535")
+		assert.Nil(t, err)
+		assert.Equal(t, uint16(65535), fooVar.C)
+		fooVar, err = setField(3, "9223372036854775807")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(9223372036854775807), fooVar.D)
+		fooVar, err = setField(4, "1.5,2")
+		assert.Nil(t, err)
+		assert.Equal(t, []float32{1.5, 2}, fooVar.E)
+		fooVar, err = setField(5, "1,2")
+		assert.Nil(t, err)
+		assert.Equal(t, []uint{1, 2}, fooVar.F)
+	})
+	t.Run("returns range errors", func(t *testing.T) {
+		_, err := setField(1, "128")
+		assert.NotNil(t, err)
+		_, err = setField(2, "-1")
+		assert.NotNil(t, err)
+		_, err = setField(2, "65536")
+		assert.NotNil(t, err)
+		_, err = setField(5, "1,-2")
+		assert.NotNil(t, err)
+	})
+	t.Run("default and env", func(t *testing.T) {
+		type bar struct {
+			A float32 `yagclif:"default:0.5"`
+			B uint8   `yagclif:"env:TestNumericSetters_B"`
+			C int16   `yagclif:"default:300000"`
+		}
+		os.Setenv("TestNumericSetters_B", "255")
+		params := reflect.TypeOf(bar{})
+		param, err := newParameter(params.Field(0))
+		assert.Nil(t, err)
+		assert.NotNil(t, param)
+		param, err = newParameter(params.Field(1))
+		assert.Nil(t, err)
+		barVar := &bar{}
+		assert.Nil(t, param.setDefault(param.getValue(barVar)))
+		assert.Equal(t, uint8(255), barVar.B)
+		_, err = newParameter(params.Field(2))
+		assert.NotNil(t, err)
+	})
+}
+
+func TestTimeSetters(t *testing.T) {
+	type foo struct {
+		Timeout time.Duration `yagclif:"default:1m30s"`
+		Since   time.Time
+		Day     time.Time       `yagclif:"layout:DateOnly;default:2020-01-02"`
+		Days    []time.Time     `yagclif:"layout:2006-01-02;delimiter:,"`
+		Delays  []time.Duration `yagclif:"delimiter:,"`
+	}
+	fooType := reflect.TypeOf(foo{})
+	setField := func(index int, value string) (*foo, error) {
+		param, err := newParameter(fooType.Field(index))
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		callBack, err := param.SetterCallback(fooVar)
+		assert.Nil(t, err)
+		return fooVar, callBack(value)
+	}
+	t.Run("works", func(t *testing.T) {
+		fooVar, err := setField(0, "2h")
+		assert.Nil(t, err)
+		assert.Equal(t, 2*time.Hour, fooVar.Timeout)
+		fooVar, err = setField(1, "2020-01-02T03:04:05Z")
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), fooVar.Since)
+		fooVar, err = setField(2, "2021-03-04")
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC), fooVar.Day)
+		fooVar, err = setField(3, "2021-03-04,2021-03-05")
+		assert.Nil(t, err)
+		assert.Len(t, fooVar.Days, 2)
+		fooVar, err = setField(4, "1s,1ms")
+		assert.Nil(t, err)
+		assert.Equal(t, []time.Duration{time.Second, time.Millisecond}, fooVar.Delays)
+	})
+	t.Run("returns errors", func(t *testing.T) {
+		_, err := setField(0, "2 hours")
+		assert.NotNil(t, err)
+		_, err = setField(1, "2020-01-02")
+		assert.NotNil(t, err)
+		_, err = setField(2, "2020-01-02T03:04:05Z")
+		assert.NotNil(t, err)
+	})
+	t.Run("defaults", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		assert.Nil(t, params.assignDefaults(fooVar))
+		assert.Equal(t, 90*time.Second, fooVar.Timeout)
+		assert.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), fooVar.Day)
+	})
+	t.Run("layout on non time type", func(t *testing.T) {
+		type bar struct {
+			A int `yagclif:"layout:DateOnly"`
+		}
+		_, err := newParameter(reflect.TypeOf(bar{}).Field(0))
+		assert.NotNil(t, err)
+	})
+}
+
+// Domain type parsed by its encoding.TextUnmarshaler implementation.
+type textLevel int
+
+func (l *textLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return fmt.Errorf("unknown level %s", text)
+	}
+	return nil
+}
+
+func (l textLevel) MarshalText() ([]byte, error) {
+	if l == 1 {
+		return []byte("low"), nil
+	}
+	return []byte("high"), nil
+}
+
+func TestTextUnmarshalerSetters(t *testing.T) {
+	type foo struct {
+		IP     net.IP
+		Big    big.Int
+		Level  textLevel   `yagclif:"default:low"`
+		Levels []textLevel `yagclif:"delimiter:,"`
+	}
+	fooType := reflect.TypeOf(foo{})
+	setField := func(index int, value string) (*foo, error) {
+		param, err := newParameter(fooType.Field(index))
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		callBack, err := param.SetterCallback(fooVar)
+		assert.Nil(t, err)
+		return fooVar, callBack(value)
+	}
+	t.Run("works", func(t *testing.T) {
+		fooVar, err := setField(0, "127.0.0.1")
+		assert.Nil(t, err)
+		assert.Equal(t, "127.0.0.1", fooVar.IP.String())
+		fooVar, err = setField(1, "123456789012345678901234567890")
+		assert.Nil(t, err)
+		assert.Equal(t, "123456789012345678901234567890", fooVar.Big.String())
+		fooVar, err = setField(2, "high")
+		assert.Nil(t, err)
+		assert.Equal(t, textLevel(2), fooVar.Level)
+		fooVar, err = setField(3, "high,low")
+		assert.Nil(t, err)
+		assert.Equal(t, []textLevel{2, 1}, fooVar.Levels)
+	})
+	t.Run("returns errors", func(t *testing.T) {
+		_, err := setField(0, "127.0.0")
+		assert.NotNil(t, err)
+		_, err = setField(2, "medium")
+		assert.NotNil(t, err)
+	})
+	t.Run("parameters", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		assert.Len(t, params, 4)
+		fooVar := &foo{}
+		assert.Nil(t, params.assignDefaults(fooVar))
+		assert.Equal(t, textLevel(1), fooVar.Level)
+	})
+	t.Run("help renders default with MarshalText", func(t *testing.T) {
+		type bar struct {
+			Big big.Int `yagclif:"default:+42"`
+		}
+		param, err := newParameter(reflect.TypeOf(bar{}).Field(0))
+		assert.Nil(t, err)
+		assert.Contains(t, param.GetHelp(), "default=42")
+	})
+}
+
+func TestChoices(t *testing.T) {
+	type foo struct {
+		Format string   `yagclif:"choices:json|yaml|table;default:json"`
+		Level  int      `yagclif:"choices:1|2|3"`
+		Modes  []string `yagclif:"choices:a|b;delimiter:,"`
+	}
+	fooType := reflect.TypeOf(foo{})
+	t.Run("works", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		_, err = params.ParseArguments(fooVar, []string{"--level", "03", "--modes", "b,a"})
+		assert.Nil(t, err)
+		assert.Equal(t, &foo{Format: "json", Level: 3, Modes: []string{"b", "a"}}, fooVar)
+	})
+	t.Run("returns error listing the choices", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"--format", "xml"},
+			{"--level", "4"},
+			{"--modes", "a,c"},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			_, err = params.ParseArguments(&foo{}, args)
+			assert.NotNil(t, err, args)
+			assert.Contains(t, err.Error(), "allowed values are", args)
+		}
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		_, err = params.ParseArguments(&foo{}, []string{"--format", "xml"})
+		assert.Contains(t, err.Error(), "json, yaml, table")
+	})
+	t.Run("validates default and choices", func(t *testing.T) {
+		type bar struct {
+			A string `yagclif:"choices:a|b;default:c"`
+			B int    `yagclif:"choices:1|two"`
+			C bool   `yagclif:"choices:true"`
+		}
+		barType := reflect.TypeOf(bar{})
+		for i := 0; i < barType.NumField(); i++ {
+			_, err := newParameter(barType.Field(i))
+			assert.NotNil(t, err, barType.Field(i).Name)
+		}
+	})
+	t.Run("help lists choices", func(t *testing.T) {
+		param, err := newParameter(fooType.Field(0))
+		assert.Nil(t, err)
+		assert.Contains(t, param.GetHelp(), "(choices=json|yaml|table;default=json)")
+	})
+}
+
 func TestFillParameter(t *testing.T) {
 	t.Run("Works", func(t *testing.T) {
 		param := &parameter{}
@@ -287,7 +583,7 @@ func TestParamHelp(t *testing.T) {
 			tipe: reflect.TypeOf(true),
 		}
 		help := param.GetHelp()
-		stringContains(help, "--bar", "bool")
+		stringContains(help, "--[no-]bar", "bool")
 		stringDoesnotContain(help, ":", "mandatory")
 	})
 	t.Run("string type", func(t *testing.T) {
@@ -335,6 +631,60 @@ func TestParamHelp(t *testing.T) {
 		help := param.GetHelp()
 		stringContains(help, "--bar", "[]int", "delimiter", "whitespace", "some int array", "mandatory")
 	})
+	t.Run("numeric types", func(t *testing.T) {
+		param := parameter{
+			name: "Bar",
+			tipe: reflect.TypeOf([]uint16{}),
+		}
+		stringContains(param.GetHelp(), "--bar", "[]uint16")
+		param.tipe = reflect.TypeOf(float64(1))
+		stringContains(param.GetHelp(), "--bar", "float64")
+	})
+	t.Run("time types", func(t *testing.T) {
+		param := parameter{
+			name: "Bar",
+			tipe: reflect.TypeOf(time.Second),
+		}
+		stringContains(param.GetHelp(), "--bar", "time.Duration")
+		stringDoesnotContain(param.GetHelp(), "layout")
+		param.tipe = reflect.TypeOf(time.Time{})
+		stringContains(param.GetHelp(), "--bar", "time.Time", "layout", time.RFC3339)
+		param.layout = "DateOnly"
+		stringContains(param.GetHelp(), "layout 2006-01-02")
+	})
+	t.Run("ranges", func(t *testing.T) {
+		param := parameter{
+			name: "Port",
+			tipe: reflect.TypeOf(0),
+			min:  "1",
+			max:  "65535",
+		}
+		stringContains(param.GetHelp(), "--port int (range=1..65535)")
+		param.tipe, param.min = reflect.TypeOf(""), ""
+		stringContains(param.GetHelp(), "--port string (length=..65535)")
+	})
+	t.Run("pattern", func(t *testing.T) {
+		param := parameter{
+			name:    "Name",
+			tipe:    reflect.TypeOf(""),
+			pattern: regexp.MustCompile("^[a-z]+$"),
+		}
+		stringContains(param.GetHelp(), "--name string (pattern=^[a-z]+$)")
+	})
+	t.Run("positional", func(t *testing.T) {
+		param := parameter{
+			name:       "Source",
+			tipe:       reflect.TypeOf(""),
+			positional: true,
+			position:   1,
+		}
+		stringContains(param.GetHelp(), "<source> string position 1")
+		stringDoesnotContain(param.GetHelp(), "--source")
+		param.positional, param.rest = false, true
+		param.tipe = reflect.TypeOf([]string{})
+		stringContains(param.GetHelp(), "<source>... []string")
+		stringDoesnotContain(param.GetHelp(), "position")
+	})
 }
 
 func TestValidate(t *testing.T) {
@@ -361,6 +711,94 @@ func TestValidate(t *testing.T) {
 		assert.NotNil(t, err)
 		assert.Nil(t, param)
 	})
+	t.Run("error on invalid positionals", func(t *testing.T) {
+		type baz struct {
+			Flag  bool
+			Name  string
+			Names []string
+		}
+		for _, testCase := range []struct {
+			field int
+			tag   reflect.StructTag
+		}{
+			{0, `yagclif:"positional:0"`},
+			{1, `yagclif:"positional:-1"`},
+			{1, `yagclif:"positional:x"`},
+			{1, `yagclif:"positional:0;shortname:n"`},
+			{1, `yagclif:"rest"`},
+			{2, `yagclif:"positional:0;rest"`},
+		} {
+			field := reflect.TypeOf(baz{}).Field(testCase.field)
+			field.Tag = testCase.tag
+			param, err := newParameter(field)
+			assert.NotNil(t, err, testCase.tag)
+			assert.Nil(t, param, testCase.tag)
+		}
+	})
+	t.Run("error on invalid ranges", func(t *testing.T) {
+		type baz struct {
+			Flag  bool
+			Count int
+			Names []string
+			Since time.Time
+		}
+		for _, testCase := range []struct {
+			field int
+			tag   reflect.StructTag
+		}{
+			{0, `yagclif:"min:1"`},
+			{1, `yagclif:"min:x"`},
+			{1, `yagclif:"min:3;max:2"`},
+			{1, `yagclif:"max:2;default:3"`},
+			{2, `yagclif:"min:-1"`},
+			{3, `yagclif:"max:2"`},
+		} {
+			field := reflect.TypeOf(baz{}).Field(testCase.field)
+			field.Tag = testCase.tag
+			param, err := newParameter(field)
+			assert.NotNil(t, err, testCase.tag)
+			assert.Nil(t, param, testCase.tag)
+		}
+	})
+	t.Run("error on invalid patterns", func(t *testing.T) {
+		type baz struct {
+			Count int
+			Name  string
+		}
+		for _, testCase := range []struct {
+			field int
+			tag   reflect.StructTag
+		}{
+			{0, `yagclif:"pattern:[0-9]+"`},
+			{1, `yagclif:"pattern:'[a-z'"`},
+			{1, `yagclif:"patternhint:lowercase"`},
+			{1, `yagclif:"pattern:^[a-z]+$;default:ABC"`},
+		} {
+			field := reflect.TypeOf(baz{}).Field(testCase.field)
+			field.Tag = testCase.tag
+			param, err := newParameter(field)
+			assert.NotNil(t, err, testCase.tag)
+			assert.Nil(t, param, testCase.tag)
+		}
+	})
+	t.Run("error on invalid aliases", func(t *testing.T) {
+		type baz struct {
+			Name string
+		}
+		for _, tag := range []reflect.StructTag{
+			`yagclif:"alias"`,
+			`yagclif:"alias:a|"`,
+			`yagclif:"alias:-n"`,
+			`yagclif:"alias:a=b"`,
+			`yagclif:"alias:a;positional:0"`,
+		} {
+			field := reflect.TypeOf(baz{}).Field(0)
+			field.Tag = tag
+			param, err := newParameter(field)
+			assert.NotNil(t, err, tag)
+			assert.Nil(t, param, tag)
+		}
+	})
 	t.Run("error array with empty delimiter", func(t *testing.T) {
 		field := reflect.TypeOf(foo{}).Field(0)
 		field.Tag = `yagclif:"delimiter:-"`
@@ -11,23 +11,31 @@ import (
 
 type parameters []*parameter
 
-func isSupportedType(sf reflect.StructField) bool {
-	supportedTypes := []reflect.Type{
-		reflect.TypeOf(true),
-		reflect.TypeOf(1), reflect.TypeOf(""),
-		reflect.TypeOf([]string{}),
-		reflect.TypeOf([]int{}),
-	}
-	for _, supportedType := range supportedTypes {
-		if supportedType == sf.Type {
-			return true
-		}
-	}
-	return false
+// Validator is implemented by structs checking their
+// values once parsed : an end after a start...
+type Validator interface {
+	Validate() error
 }
 
+// Argument after which every argument is
+// treated as a non-flag argument.
+const endOfOptions = "--"
+
 // Returns the parameters from an object tags.
 func newParameters(tipe reflect.Type) (parameters, error) {
+	params, err := collectParameters(tipe)
+	if err != nil {
+		return nil, err
+	}
+	if err = params.checkValidity(); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// Returns the parameters of the fields of a struct
+// and of its embedded structs.
+func collectParameters(tipe reflect.Type) (parameters, error) {
 	params := parameters{}
 	err := catch.Error(func() {
 		tipe.NumField()
@@ -41,28 +49,38 @@ func newParameters(tipe reflect.Type) (parameters, error) {
 		if err != nil {
 			return nil, err
 		}
-		if param != nil && isSupportedType(field) {
+		if param != nil && isSupportedType(field.Type) {
 			params = append(params, param)
 		} else if field.Tag.Get(tagName) != "omit" {
-			inheritedParams, err := newParameters(field.Type)
+			inheritedParams, err := collectParameters(field.Type)
 			if err != nil {
 				return nil, fmt.Errorf("%s\r\n error parsing recursively field %s  ", err, field.Name)
 			}
 			params = append(params, inheritedParams...)
 		}
 	}
-	if err = params.checkValidity(); err != nil {
-		return nil, err
-	}
 	return params, nil
 }
 
 // Validates that no conflict exists between parameter names.
 // and that every array parameter has a delimiter
 func (params *parameters) checkValidity() error {
+	if err := params.checkPositionals(); err != nil {
+		return err
+	}
+	if err := params.checkGroupDefinitions(); err != nil {
+		return err
+	}
+	if err := params.resolveRequirements(); err != nil {
+		return err
+	}
 	existingNames := make(map[string]*parameter, 0)
 	for _, param := range *params {
-		for _, name := range param.CliNames() {
+		names := param.CliNames()
+		if param.isBool() {
+			names = append(names, param.negatedNames()...)
+		}
+		for _, name := range names {
 			conflictingParam := existingNames[name]
 			if conflictingParam != nil {
 				return fmt.Errorf(
@@ -73,9 +91,171 @@ func (params *parameters) checkValidity() error {
 			existingNames[name] = param
 		}
 	}
+	for _, param := range *params {
+		if len(param.shortName) > 1 && params.spellsShortNames(strings.ToLower(param.shortName), param) {
+			return fmt.Errorf(
+				"ambiguous short name %s%s of struct field %s can be read as a group of short names",
+				shortNamePrefix, param.shortName, param.name,
+			)
+		}
+	}
 	return nil
 }
 
+// Returns if the string can be read as a group of short names
+// of other parameters than param : -mi as -m -i.
+func (params *parameters) spellsShortNames(s string, param *parameter) bool {
+	for _, other := range *params {
+		short := strings.ToLower(other.shortName)
+		if other == param || !other.hasShortName() || !strings.HasPrefix(s, short) {
+			continue
+		}
+		rest := s[len(short):]
+		// flags are followed by other short names,
+		// other parameters take the rest as value.
+		if rest == "" || !(other.isBool() || other.counter) || params.spellsShortNames(rest, param) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validates that positions are unique and numbered from 0
+// without gaps and that only one parameter collects the rest.
+func (params *parameters) checkPositionals() error {
+	positions := make(map[int]*parameter, 0)
+	var rest *parameter
+	for _, param := range *params {
+		if param.rest && rest != nil {
+			return fmt.Errorf(
+				"conflict for rest arguments struct fields %s and %s",
+				param.name, rest.name,
+			)
+		} else if param.rest {
+			rest = param
+		}
+		if !param.positional {
+			continue
+		}
+		conflictingParam := positions[param.position]
+		if conflictingParam != nil {
+			return fmt.Errorf(
+				"conflict for position %d struct fields %s and %s",
+				param.position, param.name, conflictingParam.name,
+			)
+		}
+		positions[param.position] = param
+	}
+	for i := 0; i < len(positions); i++ {
+		if positions[i] == nil {
+			return fmt.Errorf("missing positional argument %d", i)
+		}
+	}
+	return nil
+}
+
+// Returns the names of the groups in order of
+// appearance and the parameters of each group.
+func (params *parameters) groups() ([]string, map[string]parameters) {
+	names, members := []string{}, map[string]parameters{}
+	for _, param := range *params {
+		if param.group == "" {
+			continue
+		}
+		if members[param.group] == nil {
+			names = append(names, param.group)
+		}
+		members[param.group] = append(members[param.group], param)
+	}
+	return names, members
+}
+
+// Validates that every group has at least two parameters
+// with the same exclusive and atleastone constraints.
+func (params *parameters) checkGroupDefinitions() error {
+	names, members := params.groups()
+	for _, name := range names {
+		first := members[name][0]
+		if len(members[name]) < 2 {
+			return fmt.Errorf(
+				"group %s of struct field %s needs at least two members",
+				name, first.name,
+			)
+		}
+		for _, param := range members[name][1:] {
+			if param.exclusive != first.exclusive || param.atLeastOne != first.atLeastOne {
+				return fmt.Errorf(
+					"group %s struct fields %s and %s have different constraints",
+					name, first.name, param.name,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the description of the constraint of a group.
+func (params *parameters) groupConstraint() string {
+	first := (*params)[0]
+	if first.exclusive && first.atLeastOne {
+		return "exactly one"
+	} else if first.exclusive {
+		return "mutually exclusive"
+	}
+	return "at least one"
+}
+
+// Finds a parameter by its struct field name.
+func (params *parameters) findField(name string) *parameter {
+	for _, param := range *params {
+		if param.name == name {
+			return param
+		}
+	}
+	return nil
+}
+
+// Resolves the struct fields referenced by the requires
+// and requiredif constraints and parses requiredif values.
+func (params *parameters) resolveRequirements() error {
+	for _, param := range *params {
+		param.requiredParams = parameters{}
+		for _, name := range param.requires {
+			required := params.findField(name)
+			if required == nil || required == param {
+				return fmt.Errorf("parameter %s : requires invalid field %s", param.name, name)
+			}
+			param.requiredParams = append(param.requiredParams, required)
+		}
+		if param.requiredIf.key == "" {
+			continue
+		}
+		other := params.findField(param.requiredIf.key)
+		if other == nil || other == param || other.IsArrayType() || other.IsMapType() {
+			return fmt.Errorf(
+				"parameter %s : requiredif invalid field %s",
+				param.name, param.requiredIf.key,
+			)
+		}
+		value := reflect.New(other.tipe).Elem()
+		if err := other.setterOnValue(value)(param.requiredIf.value); err != nil {
+			return fmt.Errorf("parameter %s : requiredif %s", param.name, err)
+		}
+		param.requiredIfParam, param.requiredIfValue = other, value
+	}
+	return nil
+}
+
+// Returns the cli names of the parameters
+// separated by | : (--json | --yaml).
+func (params *parameters) formatGroup() string {
+	names := []string{}
+	for _, param := range *params {
+		names = append(names, param.CliNames()[0])
+	}
+	return fmt.Sprint("(", strings.Join(names, " | "), ")")
+}
+
 // Finds a parameter in the array by cli names :
 // -name or --shortname.
 func (params *parameters) find(s string) *parameter {
@@ -87,11 +267,99 @@ func (params *parameters) find(s string) *parameter {
 	return nil
 }
 
-// Returns an array describing the parameters.
-func (params *parameters) getHelp() []string {
+// A parameter matched in a group of short names
+// with its attached value if any : -abc or -n5.
+type shortNameMatch struct {
+	param    *parameter
+	value    string
+	hasValue bool
+}
+
+// Finds the parameters whose short name starts the string.
+func (params *parameters) findShortNamePrefixes(s string) parameters {
+	found := parameters{}
+	for _, param := range *params {
+		if param.hasShortName() && strings.HasPrefix(s, strings.ToLower(param.shortName)) {
+			found = append(found, param)
+		}
+	}
+	return found
+}
+
+// Splits a group of short names : -abc expands to -a -b -c when
+// each parameter is a flag and -n5 to -n 5 for a valued parameter.
+// Returns nil if the argument is not a group of short names.
+func (params *parameters) splitShortNames(arg string) ([]shortNameMatch, error) {
+	if !strings.HasPrefix(arg, shortNamePrefix) || strings.HasPrefix(arg, namePrefix) {
+		return nil, nil
+	}
+	group := strings.TrimPrefix(arg, shortNamePrefix)
+	// a bare - is a non-flag argument : stdin or stdout.
+	if group == "" {
+		return nil, nil
+	}
+	matches := []shortNameMatch{}
+	for group != "" {
+		candidates := params.findShortNamePrefixes(group)
+		if len(candidates) == 0 {
+			return nil, nil
+		} else if len(candidates) > 1 {
+			names := []string{}
+			for _, candidate := range candidates {
+				names = append(names, fmt.Sprint(shortNamePrefix, candidate.shortName))
+			}
+			return nil, fmt.Errorf(
+				"ambiguous argument %s matches short names %s",
+				arg, strings.Join(names, " "),
+			)
+		}
+		param := candidates[0]
+		group = group[len(param.shortName):]
+		if param.isBool() || param.counter {
+			matches = append(matches, shortNameMatch{param: param})
+			continue
+		}
+		matches = append(matches, shortNameMatch{
+			param: param, value: group, hasValue: group != "",
+		})
+		group = ""
+	}
+	return matches, nil
+}
+
+// Finds a boolean parameter by its negated name : --no-name.
+func (params *parameters) findNegated(s string) *parameter {
+	for _, param := range *params {
+		if param.MatchesNegated(s) {
+			return param
+		}
+	}
+	return nil
+}
+
+// Returns an array describing the parameters
+// followed by their groups, deprecated parameters
+// are only described in verbose help.
+func (params *parameters) getHelp(verbose bool) []string {
 	var buffer []string
 	for _, param := range *params {
-		buffer = append(buffer, param.GetHelp())
+		if verbose || param.deprecated == "" {
+			buffer = append(buffer, param.GetHelp())
+		}
+	}
+	names, members := params.groups()
+	for _, name := range names {
+		group := parameters{}
+		for _, param := range members[name] {
+			if verbose || param.deprecated == "" {
+				group = append(group, param)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+		line := fmt.Sprint("group ", name, " ", group.formatGroup(), " : ", group.groupConstraint())
+		buffer = append(buffer, line)
 	}
 	return buffer
 }
@@ -107,6 +375,75 @@ func (params *parameters) assignDefaults(obj interface{}) error {
 	return nil
 }
 
+// Checks the bounds of the parameters set from the cli
+// and the length of the rest parameter even without value.
+func (params *parameters) checkRanges(obj interface{}) error {
+	for _, param := range *params {
+		if !param.used && !param.rest {
+			continue
+		}
+		if err := param.checkRange(param.getValue(obj)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Checks that at most one parameter of each exclusive
+// group is used and that at least one parameter of
+// each atleastone group is set.
+func (params *parameters) checkGroups(obj interface{}) error {
+	names, members := params.groups()
+	for _, name := range names {
+		group, used, set := members[name], parameters{}, false
+		for _, param := range group {
+			if param.isUsedOn(obj) {
+				used = append(used, param)
+			}
+			set = set || param.isSet(obj)
+		}
+		if group[0].exclusive && len(used) > 1 {
+			return fmt.Errorf(
+				"arguments %s of group %s can not be used together",
+				used.formatGroup(), name,
+			)
+		} else if group[0].atLeastOne && !set {
+			return fmt.Errorf(
+				"one of the arguments %s of group %s is required",
+				group.formatGroup(), name,
+			)
+		}
+	}
+	return nil
+}
+
+// Checks that the parameters required by the used
+// parameters are set and that parameters required
+// by the value of another parameter are used.
+func (params *parameters) checkRequirements(obj interface{}) error {
+	for _, param := range *params {
+		for _, required := range param.requiredParams {
+			if param.isUsedOn(obj) && !required.isSet(obj) {
+				return fmt.Errorf(
+					"argument %s requires %s",
+					param.CliNames()[0], required.CliNames()[0],
+				)
+			}
+		}
+		if param.requiredIfParam == nil || param.used {
+			continue
+		}
+		value := param.requiredIfParam.getValue(obj)
+		if reflect.DeepEqual(value.Interface(), param.requiredIfValue.Interface()) {
+			return fmt.Errorf(
+				"missing argument %s required when %s is %s",
+				param.CliNames()[0], param.requiredIfParam.CliNames()[0], param.requiredIf.value,
+			)
+		}
+	}
+	return nil
+}
+
 func (params *parameters) checkForMissingMandatory() error {
 	for _, param := range *params {
 		if param.mandatory && !param.used {
@@ -116,36 +453,164 @@ func (params *parameters) checkForMissingMandatory() error {
 	return nil
 }
 
+// Returns if the argument can be bound to a positional
+// parameter : it is not a flag or it is a bare -.
+func isNonFlag(arg string) bool {
+	return arg == shortNamePrefix || !strings.HasPrefix(arg, shortNamePrefix)
+}
+
+// Binds the non-flag arguments, found at the indexes of the
+// arguments, to the positional parameters and the leftovers
+// to the rest parameter if any.
+// Returns the arguments that were not bound.
+func (params *parameters) bindPositionals(obj interface{}, args []string, nonFlags []int) ([]string, error) {
+	bound := map[int]bool{}
+	positionals := 0
+	var rest *parameter
+	for _, param := range *params {
+		if param.rest {
+			rest = param
+		}
+		if !param.positional || param.position >= len(nonFlags) {
+			continue
+		}
+		index := nonFlags[param.position]
+		if err := param.SetValue(obj, args[index]); err != nil {
+			return nil, err
+		}
+		bound[index] = true
+		positionals++
+	}
+	if leftovers := nonFlags[positionals:]; rest != nil && len(leftovers) > 0 {
+		values := []string{}
+		for _, index := range leftovers {
+			values = append(values, args[index])
+			bound[index] = true
+		}
+		if err := rest.SetRest(obj, values); err != nil {
+			return nil, err
+		}
+	}
+	remainingArgs := []string{}
+	for i, arg := range args {
+		if !bound[i] {
+			remainingArgs = append(remainingArgs, arg)
+		}
+	}
+	return remainingArgs, nil
+}
+
+// Splits an argument of the form --name=value or -n=value.
+func splitInlineValue(arg string) (name string, value string, inline bool) {
+	if !strings.HasPrefix(arg, shortNamePrefix) {
+		return "", "", false
+	}
+	parts := strings.SplitN(arg, inlineValueDelimiter, 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// Applies the argument if it matches a parameter and returns
+// the callback expecting the value of the parameter if any.
+func (params *parameters) matchArgument(obj interface{}, arg string) (callback func(string) error, matched bool, err error) {
+	if param := params.find(arg); param != nil {
+		param.warnIfDeprecated()
+		callback, err = param.SetterCallback(obj)
+		return callback, true, err
+	}
+	if name, value, inline := splitInlineValue(arg); inline {
+		if param := params.find(name); param != nil {
+			param.warnIfDeprecated()
+			return nil, true, param.SetValue(obj, value)
+		}
+	}
+	if param := params.findNegated(arg); param != nil {
+		param.warnIfDeprecated()
+		return nil, true, param.Negate(obj)
+	}
+	matches, err := params.splitShortNames(arg)
+	if err != nil || matches == nil {
+		return nil, err != nil, err
+	}
+	for _, match := range matches {
+		match.param.warnIfDeprecated()
+		if match.hasValue {
+			err = match.param.SetValue(obj, match.value)
+		} else {
+			callback, err = match.param.SetterCallback(obj)
+		}
+		if err != nil {
+			return nil, true, err
+		}
+	}
+	return callback, true, nil
+}
+
 // Fills the object with the argument.
 // This function only works if the obj
-// value is not nil.
+// value is not nil. The object is validated
+// last if it implements Validator.
 func (params *parameters) ParseArguments(obj interface{}, args []string) ([]string, error) {
-	params.assignDefaults(obj)
+	if err := params.assignDefaults(obj); err != nil {
+		return nil, err
+	}
 	remainingArgs := []string{}
+	// indexes of the remaining arguments
+	// that can be bound to positionals.
+	nonFlags := []int{}
 	var callback func(string) error
-	for _, arg := range args {
-		param := params.find(arg)
-		if callback == nil {
-			if param != nil {
-				var err error
-				callback, err = param.SetterCallback(obj)
-				if err != nil {
-					return nil, err
-				}
-			} else {
-				remainingArgs = append(remainingArgs, arg)
-			}
-		} else {
+	for i, arg := range args {
+		if callback != nil {
 			err := callback(arg)
 			if err != nil {
 				return nil, err
 			}
 			callback = nil
+			continue
+		}
+		if arg == endOfOptions {
+			for _, operand := range args[i+1:] {
+				nonFlags = append(nonFlags, len(remainingArgs))
+				remainingArgs = append(remainingArgs, operand)
+			}
+			break
+		}
+		var matched bool
+		var err error
+		callback, matched, err = params.matchArgument(obj, arg)
+		if err != nil {
+			return nil, err
+		}
+		if !matched && isNonFlag(arg) {
+			nonFlags = append(nonFlags, len(remainingArgs))
+		}
+		if !matched {
+			remainingArgs = append(remainingArgs, arg)
 		}
 	}
+	remainingArgs, err := params.bindPositionals(obj, remainingArgs, nonFlags)
+	if err != nil {
+		return nil, err
+	}
+	if err := params.checkRanges(obj); err != nil {
+		return nil, err
+	}
 	if err := params.checkForMissingMandatory(); err != nil {
 		return nil, err
 	}
+	if err := params.checkRequirements(obj); err != nil {
+		return nil, err
+	}
+	if err := params.checkGroups(obj); err != nil {
+		return nil, err
+	}
+	if validator, ok := obj.(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
 	return remainingArgs, nil
 }
 
@@ -160,7 +625,7 @@ func Parse(obj interface{}) (remainingArgs []string, err error) {
 		return nil, fmt.Errorf(
 			"%s\r\nusage:\r\n%s\r\n",
 			err, strings.Join(
-				params.getHelp(),
+				params.getHelp(false),
 				"\r\n",
 			),
 		)
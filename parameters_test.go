@@ -1,9 +1,12 @@
 package yagclif
 
 import (
+	"fmt"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -102,7 +105,7 @@ func TestFind(t *testing.T) {
 func TestParamsGetHelp(t *testing.T) {
 	params, err := newParameters(validStructType)
 	assert.Nil(t, err)
-	help := params.getHelp()
+	help := params.getHelp(false)
 	assert.Len(t, help, 3)
 }
 func TestAssignDefault(t *testing.T) {
@@ -236,6 +239,1148 @@ func TestParseArguments(t *testing.T) {
 		assert.NotNil(t, err)
 	})
 }
+func TestParsePointerArguments(t *testing.T) {
+	type foo struct {
+		Retries *int
+		Name    *string `yagclif:"env:TestParsePointerArguments_Name"`
+		Debug   *bool
+		Ratio   *float64 `yagclif:"default:0.5"`
+		Tags    *[]string
+	}
+	fooType := reflect.TypeOf(foo{})
+	t.Run("stays nil when absent", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		remaining, err := params.ParseArguments(fooVar, []string{})
+		assert.Nil(t, err)
+		assert.Empty(t, remaining)
+		assert.Nil(t, fooVar.Retries)
+		assert.Nil(t, fooVar.Name)
+		assert.Nil(t, fooVar.Debug)
+		assert.Nil(t, fooVar.Tags)
+		assert.Equal(t, 0.5, *fooVar.Ratio)
+	})
+	t.Run("is set when provided", func(t *testing.T) {
+		os.Setenv("TestParsePointerArguments_Name", "john")
+		defer os.Unsetenv("TestParsePointerArguments_Name")
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		_, err = params.ParseArguments(fooVar, []string{"--retries", "0", "--debug", "--tags", "a;b"})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, *fooVar.Retries)
+		assert.Equal(t, "john", *fooVar.Name)
+		assert.True(t, *fooVar.Debug)
+		assert.Equal(t, []string{"a", "b"}, *fooVar.Tags)
+	})
+	t.Run("returns error and stays nil", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		_, err = params.ParseArguments(fooVar, []string{"--retries", "none"})
+		assert.NotNil(t, err)
+		assert.Nil(t, fooVar.Retries)
+	})
+	t.Run("help", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		assert.Contains(t, params[0].GetHelp(), "--retries *int")
+		assert.Contains(t, params[3].GetHelp(), "default=0.5")
+	})
+	t.Run("bool pointer with default", func(t *testing.T) {
+		type bar struct {
+			Debug *bool `yagclif:"default:true"`
+		}
+		params, err := newParameters(reflect.TypeOf(bar{}))
+		assert.Nil(t, err)
+		barVar := &bar{}
+		_, err = params.ParseArguments(barVar, []string{"--no-debug"})
+		assert.Nil(t, err)
+		assert.False(t, *barVar.Debug)
+	})
+}
+
+func TestParseArrayArguments(t *testing.T) {
+	type foo struct {
+		Flags  []bool          `yagclif:"delimiter:,"`
+		Ratios []float64       `yagclif:"delimiter:,;default:0.5,1.5"`
+		Delays []time.Duration `yagclif:"delimiter:,"`
+		RGB    [3]uint8        `yagclif:"delimiter:,"`
+	}
+	fooType := reflect.TypeOf(foo{})
+	t.Run("works", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		_, err = params.ParseArguments(fooVar, []string{
+			"--flags", "true,false", "--delays", "1s,2m", "--rgb", "255,0,127",
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, &foo{
+			Flags:  []bool{true, false},
+			Ratios: []float64{0.5, 1.5},
+			Delays: []time.Duration{time.Second, 2 * time.Minute},
+			RGB:    [3]uint8{255, 0, 127},
+		}, fooVar)
+	})
+	t.Run("returns error on array length", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		_, err = params.ParseArguments(&foo{}, []string{"--rgb", "255,0"})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "--rgb expects 3 values but found 2")
+	})
+	t.Run("returns error on element", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		_, err = params.ParseArguments(&foo{}, []string{"--flags", "true,maybe"})
+		assert.NotNil(t, err)
+	})
+	t.Run("help", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		assert.Contains(t, params[0].GetHelp(), "--flags []bool delimiter ,")
+		assert.Contains(t, params[1].GetHelp(), "default=0.5,1.5")
+		assert.Contains(t, params[3].GetHelp(), "--rgb [3]uint8 delimiter ,")
+	})
+}
+
+func TestParseAccumulatedArguments(t *testing.T) {
+	type foo struct {
+		Tags  []string `yagclif:"accumulate;shortname:t;default:none"`
+		Ports []int    `yagclif:"accumulate;delimiter:,"`
+		Names []string
+	}
+	fooType := reflect.TypeOf(foo{})
+	t.Run("works", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		_, err = params.ParseArguments(fooVar, []string{
+			"--tags", "a", "-t", "b", "--tags=c", "--ports", "1,2", "--ports", "3",
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, &foo{
+			Tags:  []string{"a", "b", "c"},
+			Ports: []int{1, 2, 3},
+		}, fooVar)
+	})
+	t.Run("keeps default when absent", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		_, err = params.ParseArguments(fooVar, []string{})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"none"}, fooVar.Tags)
+	})
+	t.Run("rejects duplicates without accumulate", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		_, err = params.ParseArguments(&foo{}, []string{"--names", "a", "--names", "b"})
+		assert.NotNil(t, err)
+	})
+	t.Run("help", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		assert.Contains(t, params[0].GetHelp(), "(repeatable;default=none)")
+	})
+	t.Run("accumulate on non slice type", func(t *testing.T) {
+		type bar struct {
+			A int       `yagclif:"accumulate"`
+			B [2]int    `yagclif:"accumulate"`
+			C *[]string `yagclif:"accumulate"`
+		}
+		barType := reflect.TypeOf(bar{})
+		for i := 0; i < barType.NumField(); i++ {
+			_, err := newParameter(barType.Field(i))
+			assert.NotNil(t, err, barType.Field(i).Name)
+		}
+	})
+}
+
+func TestParseMapArguments(t *testing.T) {
+	type foo struct {
+		Label  map[string]string `yagclif:"default:env=dev"`
+		Limit  map[string]int    `yagclif:"delimiter:,;kvdelimiter:/"`
+		Weight map[int]float64
+	}
+	fooType := reflect.TypeOf(foo{})
+	t.Run("works", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		remaining, err := params.ParseArguments(fooVar, []string{
+			"--label", "env=prod", "--label", "team=core;owner=me",
+			"--limit", "cpu/2,mem/512", "--weight", "1=0.5", "!",
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"!"}, remaining)
+		assert.Equal(t, &foo{
+			Label:  map[string]string{"env": "prod", "team": "core", "owner": "me"},
+			Limit:  map[string]int{"cpu": 2, "mem": 512},
+			Weight: map[int]float64{1: 0.5},
+		}, fooVar)
+	})
+	t.Run("defaults", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		_, err = params.ParseArguments(fooVar, []string{})
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"env": "dev"}, fooVar.Label)
+		assert.Nil(t, fooVar.Limit)
+	})
+	t.Run("returns errors", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"--label", "env"},
+			{"--limit", "cpu/two"},
+			{"--weight", "one=1"},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			_, err = params.ParseArguments(&foo{}, args)
+			assert.NotNil(t, err, args)
+		}
+	})
+	t.Run("names the flag in errors", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		_, err = params.ParseArguments(&foo{}, []string{"--label", "env"})
+		assert.EqualError(t, err, "expected key=value for --label but found env")
+	})
+	t.Run("help", func(t *testing.T) {
+		type bar struct {
+			Label map[string]int `yagclif:"default:b=2;a=1"`
+		}
+		_, err := newParameters(reflect.TypeOf(bar{}))
+		assert.NotNil(t, err)
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		assert.Contains(t, params[0].GetHelp(), "delimiter ; kvdelimiter = (default=env=dev)")
+		assert.Contains(t, params[1].GetHelp(), "delimiter , kvdelimiter /")
+	})
+	t.Run("invalid constraints", func(t *testing.T) {
+		type bar struct {
+			A int               `yagclif:"kvdelimiter:="`
+			B map[string]string `yagclif:"delimiter:=;kvdelimiter:="`
+		}
+		barType := reflect.TypeOf(bar{})
+		_, err := newParameter(barType.Field(0))
+		assert.NotNil(t, err)
+		_, err = newParameter(barType.Field(1))
+		assert.NotNil(t, err)
+	})
+}
+
+func TestParseCounterArguments(t *testing.T) {
+	type foo struct {
+		Verbose int   `yagclif:"count;shortname:v"`
+		Quiet   uint8 `yagclif:"count;shortname:q;default:1"`
+		Name    string
+	}
+	fooType := reflect.TypeOf(foo{})
+	t.Run("works", func(t *testing.T) {
+		for _, testCase := range []struct {
+			args     []string
+			expected foo
+		}{
+			{[]string{}, foo{Quiet: 1}},
+			{[]string{"-v", "-v", "-v"}, foo{Verbose: 3, Quiet: 1}},
+			{[]string{"-vvv", "--verbose", "-q"}, foo{Verbose: 4, Quiet: 2}},
+			{[]string{"--name", "-vv", "-vv"}, foo{Verbose: 2, Quiet: 1, Name: "-vv"}},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			fooVar := &foo{}
+			remaining, err := params.ParseArguments(fooVar, testCase.args)
+			assert.Nil(t, err, testCase.args)
+			assert.Empty(t, remaining, testCase.args)
+			assert.Equal(t, testCase.expected, *fooVar, testCase.args)
+		}
+	})
+	t.Run("does not match unknown short names", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		remaining, err := params.ParseArguments(fooVar, []string{"-vq", "-vvx", "--vv"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"-vvx", "--vv"}, remaining)
+		assert.Equal(t, foo{Verbose: 1, Quiet: 2}, *fooVar)
+	})
+	t.Run("help", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		assert.Contains(t, params[0].GetHelp(), "--verbose -v int (count)")
+	})
+	t.Run("count on non integer type", func(t *testing.T) {
+		type bar struct {
+			A string `yagclif:"count"`
+		}
+		_, err := newParameters(reflect.TypeOf(bar{}))
+		assert.NotNil(t, err)
+	})
+}
+
+func TestParseNegatedArguments(t *testing.T) {
+	type foo struct {
+		Color bool `yagclif:"default:true;shortname:c"`
+		Debug bool `yagclif:"env:TestParseNegatedArguments_Debug"`
+		Trace bool
+	}
+	fooType := reflect.TypeOf(foo{})
+	t.Run("works", func(t *testing.T) {
+		for _, testCase := range []struct {
+			args     []string
+			expected foo
+		}{
+			{[]string{}, foo{Color: true}},
+			{[]string{"--no-color", "--trace"}, foo{Trace: true}},
+			{[]string{"-c", "--no-trace"}, foo{Color: true}},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			fooVar := &foo{}
+			remaining, err := params.ParseArguments(fooVar, testCase.args)
+			assert.Nil(t, err, testCase.args)
+			assert.Empty(t, remaining, testCase.args)
+			assert.Equal(t, testCase.expected, *fooVar, testCase.args)
+		}
+	})
+	t.Run("env", func(t *testing.T) {
+		os.Setenv("TestParseNegatedArguments_Debug", "true")
+		defer os.Unsetenv("TestParseNegatedArguments_Debug")
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		_, err = params.ParseArguments(fooVar, []string{})
+		assert.Nil(t, err)
+		assert.True(t, fooVar.Debug)
+	})
+	t.Run("returns errors", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"--color", "--no-color"},
+			{"--no-trace", "--no-trace"},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			_, err = params.ParseArguments(&foo{}, args)
+			assert.NotNil(t, err, args)
+		}
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		remaining, err := params.ParseArguments(&foo{}, []string{"-no-c", "--no-c"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"-no-c", "--no-c"}, remaining)
+	})
+	t.Run("help", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		assert.Contains(t, params[0].GetHelp(), "--[no-]color -c bool (default=true)")
+	})
+	t.Run("default must be a boolean", func(t *testing.T) {
+		type bar struct {
+			A bool `yagclif:"default:yes"`
+		}
+		_, err := newParameters(reflect.TypeOf(bar{}))
+		assert.NotNil(t, err)
+	})
+}
+
+func TestSplitInlineValue(t *testing.T) {
+	for arg, expected := range map[string][]string{
+		"--name=value": {"--name", "value"},
+		"-n=-5":        {"-n", "-5"},
+		"--label=a=b":  {"--label", "a=b"},
+		"--empty=":     {"--empty", ""},
+	} {
+		name, value, inline := splitInlineValue(arg)
+		assert.True(t, inline, arg)
+		assert.Equal(t, expected, []string{name, value}, arg)
+	}
+	for _, arg := range []string{"--name", "name=value", "-n"} {
+		_, _, inline := splitInlineValue(arg)
+		assert.False(t, inline, arg)
+	}
+}
+
+func TestParseInlineArguments(t *testing.T) {
+	type foo struct {
+		Name    string `yagclif:"shortname:n"`
+		Offset  int
+		Debug   bool `yagclif:"default:true"`
+		Verbose int  `yagclif:"count;shortname:v"`
+		Label   map[string]string
+		Tags    []string `yagclif:"delimiter:,"`
+	}
+	fooType := reflect.TypeOf(foo{})
+	t.Run("works", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		remaining, err := params.ParseArguments(fooVar, []string{
+			"--name=hello", "--offset=-5", "--debug=false", "--verbose=2", "-v",
+			"--label=env=prod", "--tags=a,b", "--other=value",
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"--other=value"}, remaining)
+		assert.Equal(t, &foo{
+			Name:    "hello",
+			Offset:  -5,
+			Verbose: 3,
+			Label:   map[string]string{"env": "prod"},
+			Tags:    []string{"a", "b"},
+		}, fooVar)
+	})
+	t.Run("short names", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		_, err = params.ParseArguments(fooVar, []string{"-n=-x", "--debug=true"})
+		assert.Nil(t, err)
+		assert.Equal(t, "-x", fooVar.Name)
+		assert.True(t, fooVar.Debug)
+	})
+	t.Run("returns errors", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"--debug=maybe"},
+			{"--offset=five"},
+			{"--name=a", "--name=b"},
+			{"--debug", "--debug=false"},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			_, err = params.ParseArguments(&foo{}, args)
+			assert.NotNil(t, err, args)
+		}
+	})
+}
+
+func TestParseShortNameGroups(t *testing.T) {
+	type foo struct {
+		All     bool   `yagclif:"shortname:a"`
+		Brief   bool   `yagclif:"shortname:b"`
+		Verbose int    `yagclif:"shortname:v;count"`
+		Number  int    `yagclif:"shortname:n"`
+		Name    string `yagclif:"shortname:mi"`
+	}
+	fooType := reflect.TypeOf(foo{})
+	t.Run("works", func(t *testing.T) {
+		for _, testCase := range []struct {
+			args      []string
+			expected  foo
+			remaining []string
+		}{
+			{[]string{"-ab"}, foo{All: true, Brief: true}, []string{}},
+			{[]string{"-bvv", "-n5"}, foo{Brief: true, Verbose: 2, Number: 5}, []string{}},
+			{[]string{"-an", "-5"}, foo{All: true, Number: -5}, []string{}},
+			{[]string{"-avn-5", "-mihello"}, foo{All: true, Verbose: 1, Number: -5, Name: "hello"}, []string{}},
+			{[]string{"-mi", "x", "-abz", "-5"}, foo{Name: "x"}, []string{"-abz", "-5"}},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			fooVar := &foo{}
+			remaining, err := params.ParseArguments(fooVar, testCase.args)
+			assert.Nil(t, err, testCase.args)
+			assert.Equal(t, testCase.remaining, remaining, testCase.args)
+			assert.Equal(t, testCase.expected, *fooVar, testCase.args)
+		}
+	})
+	t.Run("returns errors", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"-na"},
+			{"-aa"},
+			{"-n5", "-n6"},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			_, err = params.ParseArguments(&foo{}, args)
+			assert.NotNil(t, err, args)
+		}
+	})
+	t.Run("returns ambiguity errors", func(t *testing.T) {
+		type bar struct {
+			M    bool   `yagclif:"shortname:m"`
+			Name string `yagclif:"shortname:mi"`
+		}
+		params, err := newParameters(reflect.TypeOf(bar{}))
+		assert.Nil(t, err)
+		_, err = params.ParseArguments(&bar{}, []string{"-mix"})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "ambiguous argument -mix matches short names -m -mi")
+		params, err = newParameters(reflect.TypeOf(bar{}))
+		assert.Nil(t, err)
+		barVar := &bar{}
+		_, err = params.ParseArguments(barVar, []string{"-mi", "x", "-m"})
+		assert.Nil(t, err)
+		assert.Equal(t, bar{M: true, Name: "x"}, *barVar)
+	})
+	t.Run("rejects short names read as groups", func(t *testing.T) {
+		type flags struct {
+			M    bool   `yagclif:"shortname:m"`
+			I    bool   `yagclif:"shortname:i"`
+			Name string `yagclif:"shortname:mi"`
+		}
+		type valued struct {
+			Number int    `yagclif:"shortname:n"`
+			Name   string `yagclif:"shortname:nx"`
+		}
+		type counted struct {
+			Verbose int    `yagclif:"shortname:v;count"`
+			I       string `yagclif:"shortname:i"`
+			Name    string `yagclif:"shortname:VVi"`
+		}
+		for _, tipe := range []reflect.Type{
+			reflect.TypeOf(flags{}),
+			reflect.TypeOf(valued{}),
+			reflect.TypeOf(counted{}),
+		} {
+			params, err := newParameters(tipe)
+			assert.NotNil(t, err, tipe)
+			assert.Nil(t, params, tipe)
+		}
+		_, err := newParameters(reflect.TypeOf(flags{}))
+		assert.EqualError(t, err, "ambiguous short name -mi of struct field Name can be read as a group of short names")
+	})
+}
+
+func TestParseEndOfOptions(t *testing.T) {
+	type foo struct {
+		Name  string `yagclif:"shortname:n"`
+		Debug bool
+	}
+	fooType := reflect.TypeOf(foo{})
+	for _, testCase := range []struct {
+		args      []string
+		expected  foo
+		remaining []string
+	}{
+		{[]string{"--debug", "--", "--name", "x", "-n"}, foo{Debug: true}, []string{"--name", "x", "-n"}},
+		{[]string{"a", "--", "--", "--debug"}, foo{}, []string{"a", "--", "--debug"}},
+		{[]string{"--name", "--", "--debug"}, foo{Name: "--", Debug: true}, []string{}},
+		{[]string{"--"}, foo{}, []string{}},
+		{[]string{"-", "file"}, foo{}, []string{"-", "file"}},
+	} {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		remaining, err := params.ParseArguments(fooVar, testCase.args)
+		assert.Nil(t, err, testCase.args)
+		assert.Equal(t, testCase.remaining, remaining, testCase.args)
+		assert.Equal(t, testCase.expected, *fooVar, testCase.args)
+	}
+}
+
+func TestParsePositionalArguments(t *testing.T) {
+	type foo struct {
+		Source string `yagclif:"positional:0;mandatory"`
+		Count  int    `yagclif:"positional:1;default:1;choices:1|2|3"`
+		Debug  bool
+	}
+	type bar struct {
+		Target string   `yagclif:"positional:0"`
+		Files  []string `yagclif:"rest"`
+		Sizes  []uint8  `yagclif:"rest"`
+	}
+	type baz struct {
+		Target string  `yagclif:"positional:0"`
+		Sizes  []uint8 `yagclif:"rest;delimiter:,;default:1,2"`
+	}
+	t.Run("binds positionals", func(t *testing.T) {
+		for _, testCase := range []struct {
+			args      []string
+			expected  foo
+			remaining []string
+		}{
+			{[]string{"a"}, foo{Source: "a", Count: 1}, []string{}},
+			{[]string{"a", "2"}, foo{Source: "a", Count: 2}, []string{}},
+			{[]string{"a", "--debug", "3", "b"}, foo{Source: "a", Count: 3, Debug: true}, []string{"b"}},
+			{[]string{"--", "--debug", "2"}, foo{Source: "--debug", Count: 2}, []string{}},
+			{[]string{"-", "--debug"}, foo{Source: "-", Count: 1, Debug: true}, []string{}},
+		} {
+			params, err := newParameters(reflect.TypeOf(foo{}))
+			assert.Nil(t, err)
+			fooVar := &foo{}
+			remaining, err := params.ParseArguments(fooVar, testCase.args)
+			assert.Nil(t, err, testCase.args)
+			assert.Equal(t, testCase.remaining, remaining, testCase.args)
+			assert.Equal(t, testCase.expected, *fooVar, testCase.args)
+		}
+	})
+	t.Run("collects the rest", func(t *testing.T) {
+		type qux struct {
+			Target string   `yagclif:"positional:0"`
+			Files  []string `yagclif:"rest"`
+		}
+		params, err := newParameters(reflect.TypeOf(qux{}))
+		assert.Nil(t, err)
+		quxVar := &qux{}
+		remaining, err := params.ParseArguments(quxVar, []string{"dir", "a", "b", "--", "-c"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{}, remaining)
+		assert.Equal(t, qux{Target: "dir", Files: []string{"a", "b", "-c"}}, *quxVar)
+	})
+	t.Run("leaves unknown flags", func(t *testing.T) {
+		type qux struct {
+			Target string   `yagclif:"positional:0"`
+			Files  []string `yagclif:"rest"`
+		}
+		for _, testCase := range []struct {
+			args      []string
+			expected  qux
+			remaining []string
+		}{
+			{[]string{"--verbsoe", "a", "b"}, qux{Target: "a", Files: []string{"b"}}, []string{"--verbsoe"}},
+			{[]string{"a", "-x", "-", "--", "-y"}, qux{Target: "a", Files: []string{"-", "-y"}}, []string{"-x"}},
+			{[]string{"-5"}, qux{}, []string{"-5"}},
+		} {
+			params, err := newParameters(reflect.TypeOf(qux{}))
+			assert.Nil(t, err)
+			quxVar := &qux{}
+			remaining, err := params.ParseArguments(quxVar, testCase.args)
+			assert.Nil(t, err, testCase.args)
+			assert.Equal(t, testCase.remaining, remaining, testCase.args)
+			assert.Equal(t, testCase.expected, *quxVar, testCase.args)
+		}
+	})
+	t.Run("rest keeps its default", func(t *testing.T) {
+		for _, testCase := range []struct {
+			args     []string
+			expected baz
+		}{
+			{[]string{"dir"}, baz{Target: "dir", Sizes: []uint8{1, 2}}},
+			{[]string{"dir", "3"}, baz{Target: "dir", Sizes: []uint8{3}}},
+		} {
+			params, err := newParameters(reflect.TypeOf(baz{}))
+			assert.Nil(t, err)
+			bazVar := &baz{}
+			_, err = params.ParseArguments(bazVar, testCase.args)
+			assert.Nil(t, err, testCase.args)
+			assert.Equal(t, testCase.expected, *bazVar, testCase.args)
+		}
+	})
+	t.Run("returns error", func(t *testing.T) {
+		for _, args := range [][]string{
+			{},
+			{"--debug"},
+			{"a", "4"},
+			{"a", "x"},
+		} {
+			params, err := newParameters(reflect.TypeOf(foo{}))
+			assert.Nil(t, err)
+			_, err = params.ParseArguments(&foo{}, args)
+			assert.NotNil(t, err, args)
+		}
+		params, err := newParameters(reflect.TypeOf(baz{}))
+		assert.Nil(t, err)
+		_, err = params.ParseArguments(&baz{}, []string{"dir", "1", "256"})
+		assert.NotNil(t, err)
+	})
+	t.Run("invalid definitions", func(t *testing.T) {
+		type gap struct {
+			A string `yagclif:"positional:0"`
+			B string `yagclif:"positional:2"`
+		}
+		type duplicate struct {
+			A string `yagclif:"positional:0"`
+			B string `yagclif:"positional:0"`
+		}
+		for _, tipe := range []reflect.Type{
+			reflect.TypeOf(bar{}),
+			reflect.TypeOf(gap{}),
+			reflect.TypeOf(duplicate{}),
+		} {
+			params, err := newParameters(tipe)
+			assert.NotNil(t, err, tipe)
+			assert.Nil(t, params, tipe)
+		}
+	})
+}
+
+func TestParseRangeArguments(t *testing.T) {
+	type foo struct {
+		Port    int           `yagclif:"min:1;max:65535;default:8080"`
+		Ratio   *float64      `yagclif:"min:0;max:1"`
+		Timeout time.Duration `yagclif:"min:1s"`
+		Name    string        `yagclif:"max:4"`
+		Tags    []string      `yagclif:"accumulate;min:1;max:2"`
+		Verbose int           `yagclif:"count;shortname:v;max:2"`
+	}
+	fooType := reflect.TypeOf(foo{})
+	half := 0.5
+	t.Run("works", func(t *testing.T) {
+		for _, testCase := range []struct {
+			args     []string
+			expected foo
+		}{
+			{[]string{}, foo{Port: 8080}},
+			{[]string{"--port", "1", "--ratio", "0.5"}, foo{Port: 1, Ratio: &half}},
+			{[]string{"--timeout", "1m", "--name", "abcd"}, foo{Port: 8080, Timeout: time.Minute, Name: "abcd"}},
+			{[]string{"--tags", "a", "--tags", "b", "-vv"}, foo{Port: 8080, Tags: []string{"a", "b"}, Verbose: 2}},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			fooVar := &foo{}
+			_, err = params.ParseArguments(fooVar, testCase.args)
+			assert.Nil(t, err, testCase.args)
+			assert.Equal(t, testCase.expected, *fooVar, testCase.args)
+		}
+	})
+	t.Run("returns error", func(t *testing.T) {
+		for _, testCase := range []struct {
+			args []string
+			err  string
+		}{
+			{[]string{"--port", "0"}, "invalid value 0 for --port : allowed range is 1..65535"},
+			{[]string{"--ratio", "1.5"}, "invalid value 1.5 for --ratio : allowed range is 0..1"},
+			{[]string{"--timeout", "500ms"}, "invalid value 500ms for --timeout : allowed range is 1s.."},
+			{[]string{"--name", "abcde"}, "invalid length 5 for --name : allowed range is ..4"},
+			{[]string{"--tags", "a,b", "--tags", "c;d"}, "invalid length 3 for --tags : allowed range is 1..2"},
+			{[]string{"-vvv"}, "invalid value 3 for --verbose : allowed range is ..2"},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			_, err = params.ParseArguments(&foo{}, testCase.args)
+			assert.EqualError(t, err, testCase.err, testCase.args)
+		}
+	})
+	t.Run("checks positionals without value", func(t *testing.T) {
+		type bar struct {
+			Source string `yagclif:"positional:0;min:1"`
+			Files  []int  `yagclif:"rest;min:1;max:2"`
+		}
+		for _, testCase := range []struct {
+			args []string
+			err  string
+		}{
+			{[]string{"a", "1"}, ""},
+			{[]string{"a", "1", "2"}, ""},
+			{[]string{"a"}, "invalid length 0 for <files>... : allowed range is 1..2"},
+			{[]string{}, "invalid length 0 for <files>... : allowed range is 1..2"},
+			{[]string{"", "1"}, "invalid length 0 for <source> : allowed range is 1.."},
+			{[]string{"a", "1", "2", "3"}, "invalid length 3 for <files>... : allowed range is 1..2"},
+		} {
+			params, err := newParameters(reflect.TypeOf(bar{}))
+			assert.Nil(t, err)
+			_, err = params.ParseArguments(&bar{}, testCase.args)
+			if testCase.err == "" {
+				assert.Nil(t, err, testCase.args)
+			} else {
+				assert.EqualError(t, err, testCase.err, testCase.args)
+			}
+		}
+	})
+	t.Run("optional positionals without value", func(t *testing.T) {
+		type bar struct {
+			Count int `yagclif:"positional:0;min:1"`
+		}
+		type baz struct {
+			Count int `yagclif:"positional:0;min:1;mandatory"`
+		}
+		params, err := newParameters(reflect.TypeOf(bar{}))
+		assert.Nil(t, err)
+		_, err = params.ParseArguments(&bar{}, []string{})
+		assert.Nil(t, err)
+		params, err = newParameters(reflect.TypeOf(baz{}))
+		assert.Nil(t, err)
+		_, err = params.ParseArguments(&baz{}, []string{})
+		assert.EqualError(t, err, "missing argument [<count>] for Count")
+	})
+	t.Run("checks env values", func(t *testing.T) {
+		type bar struct {
+			Workers uint `yagclif:"min:1;max:8;env:TestParseRangeArguments_Workers"`
+		}
+		os.Setenv("TestParseRangeArguments_Workers", "4")
+		params, err := newParameters(reflect.TypeOf(bar{}))
+		assert.Nil(t, err)
+		os.Setenv("TestParseRangeArguments_Workers", "9")
+		_, err = params.ParseArguments(&bar{}, []string{"--workers", "2"})
+		assert.EqualError(t, err, "invalid value 9 for --workers : allowed range is 1..8")
+		os.Unsetenv("TestParseRangeArguments_Workers")
+	})
+}
+
+func TestParsePatternArguments(t *testing.T) {
+	type foo struct {
+		Address string   `yagclif:"pattern:'^[a-z]+:[0-9]+$';default:'localhost:80'"`
+		Names   []string `yagclif:"delimiter:,;pattern:^[a-z]+$;patternhint:must be lowercase letters"`
+		Files   []string `yagclif:"rest;pattern:'\\.go$'"`
+	}
+	fooType := reflect.TypeOf(foo{})
+	t.Run("works", func(t *testing.T) {
+		for _, testCase := range []struct {
+			args     []string
+			expected foo
+		}{
+			{[]string{}, foo{Address: "localhost:80"}},
+			{[]string{"--address", "db:5432", "--names", "a,b"}, foo{Address: "db:5432", Names: []string{"a", "b"}}},
+			{[]string{"a.go", "b.go"}, foo{Address: "localhost:80", Files: []string{"a.go", "b.go"}}},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			fooVar := &foo{}
+			_, err = params.ParseArguments(fooVar, testCase.args)
+			assert.Nil(t, err, testCase.args)
+			assert.Equal(t, testCase.expected, *fooVar, testCase.args)
+		}
+	})
+	t.Run("returns error", func(t *testing.T) {
+		for _, testCase := range []struct {
+			args []string
+			err  string
+		}{
+			{[]string{"--address", "db"}, "invalid value db for --address : must match ^[a-z]+:[0-9]+$"},
+			{[]string{"--names", "a,B"}, "invalid value B for --names : must be lowercase letters"},
+			{[]string{"a.go", "b.txt"}, "invalid value b.txt for <files>... : must match \\.go$"},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			_, err = params.ParseArguments(&foo{}, testCase.args)
+			assert.EqualError(t, err, testCase.err, testCase.args)
+		}
+	})
+}
+
+func TestParseExclusiveGroups(t *testing.T) {
+	type foo struct {
+		JSON  bool   `yagclif:"group:output;exclusive"`
+		YAML  bool   `yagclif:"group:output;exclusive"`
+		Table string `yagclif:"group:output;exclusive;default:plain"`
+		Debug bool
+	}
+	fooType := reflect.TypeOf(foo{})
+	t.Run("works", func(t *testing.T) {
+		for _, testCase := range []struct {
+			args     []string
+			expected foo
+		}{
+			{[]string{}, foo{Table: "plain"}},
+			{[]string{"--json", "--debug"}, foo{JSON: true, Table: "plain", Debug: true}},
+			{[]string{"--table", "grid"}, foo{Table: "grid"}},
+			{[]string{"--json", "--no-yaml"}, foo{JSON: true, Table: "plain"}},
+			{[]string{"--yaml=false", "--json"}, foo{JSON: true, Table: "plain"}},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			fooVar := &foo{}
+			_, err = params.ParseArguments(fooVar, testCase.args)
+			assert.Nil(t, err, testCase.args)
+			assert.Equal(t, testCase.expected, *fooVar, testCase.args)
+		}
+	})
+	t.Run("returns error", func(t *testing.T) {
+		for _, testCase := range []struct {
+			args []string
+			err  string
+		}{
+			{[]string{"--json", "--yaml"}, "arguments (--json | --yaml) of group output can not be used together"},
+			{[]string{"--json", "--no-yaml", "--table", "x"}, "arguments (--json | --table) of group output can not be used together"},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			_, err = params.ParseArguments(&foo{}, testCase.args)
+			assert.EqualError(t, err, testCase.err, testCase.args)
+		}
+	})
+	t.Run("help", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		help := params.getHelp(false)
+		assert.Equal(t, 5, len(help))
+		assert.Equal(t, "group output (--json | --yaml | --table) : mutually exclusive", help[4])
+	})
+	t.Run("invalid definitions", func(t *testing.T) {
+		type single struct {
+			JSON bool `yagclif:"group:output;exclusive"`
+			YAML bool `yagclif:"group:format;exclusive"`
+		}
+		type notExclusive struct {
+			JSON bool `yagclif:"group:output"`
+			YAML bool `yagclif:"group:output"`
+		}
+		type noGroup struct {
+			JSON bool `yagclif:"exclusive"`
+		}
+		type mandatory struct {
+			JSON string `yagclif:"group:output;exclusive;mandatory"`
+			YAML bool   `yagclif:"group:output;exclusive"`
+		}
+		for _, tipe := range []reflect.Type{
+			reflect.TypeOf(single{}),
+			reflect.TypeOf(notExclusive{}),
+			reflect.TypeOf(noGroup{}),
+			reflect.TypeOf(mandatory{}),
+		} {
+			params, err := newParameters(tipe)
+			assert.NotNil(t, err, tipe)
+			assert.Nil(t, params, tipe)
+		}
+	})
+}
+
+type credentials struct {
+	User     string `yagclif:"requires:Password"`
+	Password string `yagclif:"env:TestParseRequirements_Password"`
+}
+
+func TestParseRequirements(t *testing.T) {
+	type foo struct {
+		credentials
+		Mode  string `yagclif:"choices:local|remote;default:local"`
+		Host  string `yagclif:"requiredif:Mode=remote"`
+		File  string `yagclif:"group:source;atleastone"`
+		URL   string `yagclif:"group:source;atleastone"`
+		Stdin bool   `yagclif:"group:source;atleastone"`
+	}
+	fooType := reflect.TypeOf(foo{})
+	t.Run("works", func(t *testing.T) {
+		for _, testCase := range []struct {
+			args     []string
+			expected foo
+		}{
+			{[]string{"--stdin"}, foo{Mode: "local", Stdin: true}},
+			{[]string{"--file", "a", "--url", "b"}, foo{Mode: "local", File: "a", URL: "b"}},
+			{[]string{"--file", "a", "--user", "u", "--password", "p"}, foo{credentials{"u", "p"}, "local", "", "a", "", false}},
+			{[]string{"--url", "a", "--mode", "remote", "--host", "h"}, foo{Mode: "remote", Host: "h", URL: "a"}},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			fooVar := &foo{}
+			_, err = params.ParseArguments(fooVar, testCase.args)
+			assert.Nil(t, err, testCase.args)
+			assert.Equal(t, testCase.expected, *fooVar, testCase.args)
+		}
+	})
+	t.Run("returns error", func(t *testing.T) {
+		for _, testCase := range []struct {
+			args []string
+			err  string
+		}{
+			{[]string{}, "one of the arguments (--file | --url | --stdin) of group source is required"},
+			{[]string{"--stdin", "--user", "u"}, "argument --user requires --password"},
+			{[]string{"--stdin", "--mode", "remote"}, "missing argument --host required when --mode is remote"},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			_, err = params.ParseArguments(&foo{}, testCase.args)
+			assert.EqualError(t, err, testCase.err, testCase.args)
+		}
+	})
+	t.Run("booleans are set when true", func(t *testing.T) {
+		type bar struct {
+			U       string `yagclif:"requires:D"`
+			D       bool   `yagclif:"default:false"`
+			E       bool   `yagclif:"group:e;atleastone"`
+			F       *bool  `yagclif:"group:e;atleastone"`
+			Verbose bool   `yagclif:"requires:LogFile"`
+			LogFile string
+		}
+		for _, testCase := range []struct {
+			args []string
+			err  string
+		}{
+			{[]string{"--e", "--u", "x", "--d"}, ""},
+			{[]string{"--f", "--u", "x", "--d=true"}, ""},
+			{[]string{"--e", "--u", "x"}, "argument --u requires --d"},
+			{[]string{"--e", "--u", "x", "--no-d"}, "argument --u requires --d"},
+			{[]string{"--no-e", "--f=false"}, "one of the arguments (--e | --f) of group e is required"},
+			{[]string{"--e", "--no-verbose"}, ""},
+			{[]string{"--e", "--verbose=false"}, ""},
+			{[]string{"--e", "--verbose"}, "argument --verbose requires --logfile"},
+		} {
+			params, err := newParameters(reflect.TypeOf(bar{}))
+			assert.Nil(t, err)
+			_, err = params.ParseArguments(&bar{}, testCase.args)
+			if testCase.err == "" {
+				assert.Nil(t, err, testCase.args)
+			} else {
+				assert.EqualError(t, err, testCase.err, testCase.args)
+			}
+		}
+	})
+	t.Run("env satisfies requires", func(t *testing.T) {
+		os.Setenv("TestParseRequirements_Password", "p")
+		defer os.Unsetenv("TestParseRequirements_Password")
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		_, err = params.ParseArguments(fooVar, []string{"--stdin", "--user", "u"})
+		assert.Nil(t, err)
+		assert.Equal(t, credentials{"u", "p"}, fooVar.credentials)
+	})
+	t.Run("help", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		help := params.getHelp(false)
+		assert.Contains(t, help[0], "(requires=--password)")
+		assert.Contains(t, help[3], "(requiredif=--mode=remote)")
+		assert.Equal(t, "group source (--file | --url | --stdin) : at least one", help[7])
+	})
+	t.Run("invalid definitions", func(t *testing.T) {
+		type unknown struct {
+			User string `yagclif:"requires:Password"`
+		}
+		type itself struct {
+			User string `yagclif:"requires:User"`
+		}
+		type badValue struct {
+			Port int
+			Host string `yagclif:"requiredif:Port=x"`
+		}
+		type badSyntax struct {
+			Port int
+			Host string `yagclif:"requiredif:Port"`
+		}
+		type withDefault struct {
+			Port int
+			Host string `yagclif:"requiredif:Port=1;default:h"`
+		}
+		type mixed struct {
+			File string `yagclif:"group:source;atleastone"`
+			URL  string `yagclif:"group:source;exclusive"`
+		}
+		for _, tipe := range []reflect.Type{
+			reflect.TypeOf(unknown{}),
+			reflect.TypeOf(itself{}),
+			reflect.TypeOf(badValue{}),
+			reflect.TypeOf(badSyntax{}),
+			reflect.TypeOf(withDefault{}),
+			reflect.TypeOf(mixed{}),
+		} {
+			params, err := newParameters(tipe)
+			assert.NotNil(t, err, tipe)
+			assert.Nil(t, params, tipe)
+		}
+	})
+}
+
+func TestParseExactlyOneGroup(t *testing.T) {
+	type foo struct {
+		JSON bool `yagclif:"group:output;exclusive;atleastone"`
+		YAML bool `yagclif:"group:output;exclusive;atleastone"`
+	}
+	for _, testCase := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"--json"}, ""},
+		{[]string{}, "one of the arguments (--json | --yaml) of group output is required"},
+		{[]string{"--json", "--yaml"}, "arguments (--json | --yaml) of group output can not be used together"},
+	} {
+		params, err := newParameters(reflect.TypeOf(foo{}))
+		assert.Nil(t, err)
+		_, err = params.ParseArguments(&foo{}, testCase.args)
+		if testCase.err == "" {
+			assert.Nil(t, err, testCase.args)
+		} else {
+			assert.EqualError(t, err, testCase.err, testCase.args)
+		}
+	}
+	params, err := newParameters(reflect.TypeOf(foo{}))
+	assert.Nil(t, err)
+	assert.Equal(t, "group output (--json | --yaml) : exactly one", params.getHelp(false)[2])
+}
+
+func TestParseAliasArguments(t *testing.T) {
+	type foo struct {
+		Output string `yagclif:"shortname:o;alias:out|dest;alias:Target"`
+		Color  bool   `yagclif:"alias:colour"`
+	}
+	fooType := reflect.TypeOf(foo{})
+	t.Run("works", func(t *testing.T) {
+		for _, testCase := range []struct {
+			args     []string
+			expected foo
+		}{
+			{[]string{"--output", "a"}, foo{Output: "a"}},
+			{[]string{"--out", "a", "--colour"}, foo{Output: "a", Color: true}},
+			{[]string{"--dest=a"}, foo{Output: "a"}},
+			{[]string{"--target", "a", "--no-colour"}, foo{Output: "a"}},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			fooVar := &foo{}
+			_, err = params.ParseArguments(fooVar, testCase.args)
+			assert.Nil(t, err, testCase.args)
+			assert.Equal(t, testCase.expected, *fooVar, testCase.args)
+		}
+	})
+	t.Run("returns error", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"--out", "a", "--dest", "b"},
+			{"--color", "--no-colour"},
+		} {
+			params, err := newParameters(fooType)
+			assert.Nil(t, err)
+			_, err = params.ParseArguments(&foo{}, args)
+			assert.NotNil(t, err, args)
+		}
+	})
+	t.Run("help", func(t *testing.T) {
+		params, err := newParameters(fooType)
+		assert.Nil(t, err)
+		help := params.getHelp(false)
+		assert.True(t, strings.HasPrefix(help[0], "--output -o --out --dest --target string"), help[0])
+		assert.True(t, strings.HasPrefix(help[1], "--[no-]color --[no-]colour bool"), help[1])
+	})
+	t.Run("invalid definitions", func(t *testing.T) {
+		type nameConflict struct {
+			Output string `yagclif:"alias:dest"`
+			Dest   string
+		}
+		type aliasConflict struct {
+			Output string `yagclif:"alias:dest"`
+			Target string `yagclif:"alias:Dest"`
+		}
+		type negationConflict struct {
+			Color   bool `yagclif:"alias:colour"`
+			NoColor bool `yagclif:"alias:no-colour"`
+		}
+		for _, tipe := range []reflect.Type{
+			reflect.TypeOf(nameConflict{}),
+			reflect.TypeOf(aliasConflict{}),
+			reflect.TypeOf(negationConflict{}),
+		} {
+			params, err := newParameters(tipe)
+			assert.NotNil(t, err, tipe)
+			assert.Nil(t, params, tipe)
+		}
+	})
+}
+
+type validatedRange struct {
+	Start int
+	End   int `yagclif:"mandatory"`
+}
+
+func (r validatedRange) Validate() error {
+	if r.End <= r.Start {
+		return fmt.Errorf("end must be after start")
+	}
+	return nil
+}
+
+func TestParseValidatedArguments(t *testing.T) {
+	for _, testCase := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"--start", "1", "--end", "2"}, ""},
+		{[]string{"--start", "2", "--end", "1"}, "end must be after start"},
+		{[]string{"--start", "2"}, "missing argument [--end] for End"},
+		{[]string{"--start", "x", "--end", "1"}, "invalid value x for --start : strconv.ParseInt: parsing \"x\": invalid syntax"},
+	} {
+		params, err := newParameters(reflect.TypeOf(validatedRange{}))
+		assert.Nil(t, err)
+		_, err = params.ParseArguments(&validatedRange{}, testCase.args)
+		if testCase.err == "" {
+			assert.Nil(t, err, testCase.args)
+		} else {
+			assert.EqualError(t, err, testCase.err, testCase.args)
+		}
+	}
+}
+
 func TestParse(t *testing.T) {
 	t.Run("works", func(t *testing.T) {
 		testStruct := &validStruct{}
@@ -281,6 +1426,15 @@ func TestParse(t *testing.T) {
 		assert.Nil(t, remaining)
 		assert.NotNil(t, err)
 	})
+	t.Run("return validation err with usage", func(t *testing.T) {
+		os.Args = []string{"main", "--start", "3", "--end", "1"}
+		remaining, err := Parse(&validatedRange{})
+		assert.Nil(t, remaining)
+		assert.NotNil(t, err)
+		for _, expected := range []string{"end must be after start", "usage:", "--start int", "--end int"} {
+			assert.Contains(t, err.Error(), expected)
+		}
+	})
 }
 
 func TestSetterCallBack(t *testing.T) {
@@ -117,8 +117,9 @@ func (r *route) run(args []string) error {
 }
 
 // getHelp returns an array string.
-// Each element is a line of the help text.
-func (r *route) getHelp() []string {
+// Each element is a line of the help text,
+// deprecated parameters are only listed if verbose.
+func (r *route) getHelp(verbose bool) []string {
 	if r.parameterType == nil {
 		return []string{}
 	}
@@ -126,5 +127,5 @@ func (r *route) getHelp() []string {
 	if err != nil {
 		return []string{"Could not parse parameter type"}
 	}
-	return parameters.getHelp()
+	return parameters.getHelp(verbose)
 }
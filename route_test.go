@@ -92,6 +92,17 @@ func TestGetCustomCallBack(t *testing.T) {
 		err = callback([]string{})
 		assert.NotNil(t, err)
 	})
+	t.Run("validation error", func(t *testing.T) {
+		called := false
+		callbackFunc := reflect.ValueOf(func(validatedRange, []string) {
+			called = true
+		})
+		callback, err := getCustomCallBack(callbackFunc, reflect.TypeOf(validatedRange{}))
+		assert.Nil(t, err)
+		err = callback([]string{"--end", "0"})
+		assert.EqualError(t, err, "end must be after start")
+		assert.False(t, called)
+	})
 	t.Run("panic inside callback", func(t *testing.T) {
 		callbackFunc := reflect.ValueOf(func(SomeStruct, []string) {
 			panic("hello")
@@ -162,7 +173,7 @@ func TestRouteGetHelp(t *testing.T) {
 		r := route{
 			parameterType: reflect.TypeOf(SomeStruct{}),
 		}
-		helpTexts := r.getHelp()
+		helpTexts := r.getHelp(false)
 		helpContains := func(s string) bool {
 			for _, helpText := range helpTexts {
 				if strings.Contains(helpText, s) {
@@ -182,7 +193,7 @@ func TestRouteGetHelp(t *testing.T) {
 		r := route{
 			parameterType: nil,
 		}
-		helpTexts := r.getHelp()
+		helpTexts := r.getHelp(false)
 		length := len(helpTexts)
 		assert.Equal(t, 0, length)
 	})
@@ -190,7 +201,7 @@ func TestRouteGetHelp(t *testing.T) {
 		r := route{
 			parameterType: reflect.TypeOf(true),
 		}
-		helpTexts := r.getHelp()
+		helpTexts := r.getHelp(false)
 		assert.Equal(t, []string{"Could not parse parameter type"}, helpTexts)
 	})
 }
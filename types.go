@@ -0,0 +1,311 @@
+package yagclif
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ParseFunc parses a cli value into a value of a registered type.
+type ParseFunc func(value string) (interface{}, error)
+
+// FormatFunc formats a value of a registered type for help texts.
+type FormatFunc func(value interface{}) string
+
+// Struct defining how values of a type
+// are parsed and displayed.
+type valueType struct {
+	// Name of the values displayed in the help,
+	// the go type name is displayed if empty.
+	metavar string
+	// Parses a single cli value, the returned
+	// value is assignable to the type.
+	parse func(p *parameter, value string) (reflect.Value, error)
+	// Formats a value of the type for help texts.
+	format func(p *parameter, value reflect.Value) string
+}
+
+// Types of time values.
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// Type of the encoding.TextUnmarshaler interface.
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// Type of the encoding.TextMarshaler interface.
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// Registry of the scalar types that can be parsed
+// from a single cli value. Slices of these types
+// are derived from it.
+var (
+	registryLock    sync.RWMutex
+	registeredTypes = map[reflect.Type]*valueType{}
+)
+
+func init() {
+	kindTypes := []reflect.Type{
+		reflect.TypeOf(true),
+		reflect.TypeOf(""),
+		reflect.TypeOf(int(1)), reflect.TypeOf(int8(1)),
+		reflect.TypeOf(int16(1)), reflect.TypeOf(int32(1)),
+		reflect.TypeOf(int64(1)),
+		reflect.TypeOf(uint(1)), reflect.TypeOf(uint8(1)),
+		reflect.TypeOf(uint16(1)), reflect.TypeOf(uint32(1)),
+		reflect.TypeOf(uint64(1)),
+		reflect.TypeOf(float32(1)), reflect.TypeOf(float64(1)),
+	}
+	for _, kindType := range kindTypes {
+		registeredTypes[kindType] = newKindValueType(kindType)
+	}
+	registeredTypes[durationType] = &valueType{
+		parse: func(p *parameter, value string) (reflect.Value, error) {
+			d, err := time.ParseDuration(value)
+			return reflect.ValueOf(d), err
+		},
+		format: formatSprint,
+	}
+	registeredTypes[timeType] = &valueType{
+		parse: func(p *parameter, value string) (reflect.Value, error) {
+			t, err := time.Parse(p.getLayout(), value)
+			return reflect.ValueOf(t), err
+		},
+		format: func(p *parameter, value reflect.Value) string {
+			return value.Interface().(time.Time).Format(p.getLayout())
+		},
+	}
+}
+
+// RegisterType teaches the parser a new field type.
+// parse converts a cli value into a value assignable to tipe,
+// format is optional and renders values in help texts.
+// Slices of the registered type are supported as well.
+func RegisterType(tipe reflect.Type, parse ParseFunc, format FormatFunc) error {
+	if tipe == nil || parse == nil {
+		return fmt.Errorf("type and parse function can not be nil")
+	}
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	registeredTypes[tipe] = newValueType(tipe, "", parse, format)
+	return nil
+}
+
+// Returns a valueType from the parse and format functions.
+func newValueType(tipe reflect.Type, metavar string, parse ParseFunc, format FormatFunc) *valueType {
+	newType := &valueType{
+		metavar: metavar,
+		parse: func(p *parameter, value string) (reflect.Value, error) {
+			parsed, err := parse(value)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			parsedValue := reflect.ValueOf(parsed)
+			if !parsedValue.IsValid() || !parsedValue.Type().AssignableTo(tipe) {
+				return reflect.Value{}, fmt.Errorf(
+					"parse function of %s returned a value of type %T", tipe, parsed,
+				)
+			}
+			return parsedValue, nil
+		},
+		format: formatSprint,
+	}
+	if format != nil {
+		newType.format = func(p *parameter, value reflect.Value) string {
+			return format(value.Interface())
+		}
+	}
+	return newType
+}
+
+// Returns how values of the type are parsed,
+// nil if the type is not a supported scalar type.
+func lookupType(tipe reflect.Type) *valueType {
+	registryLock.RLock()
+	registeredType := registeredTypes[tipe]
+	registryLock.RUnlock()
+	if registeredType != nil {
+		return registeredType
+	}
+	if isTextType(tipe) {
+		return newTextValueType(tipe)
+	}
+	if isEnumType(tipe) {
+		return newEnumValueType(tipe)
+	}
+	if isBasicKind(tipe) {
+		return newKindValueType(tipe)
+	}
+	return nil
+}
+
+// Returns if the type is of an integer kind.
+func isIntegerKind(tipe reflect.Type) bool {
+	switch tipe.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// Returns if values of the type can be parsed by its kind.
+func isBasicKind(tipe reflect.Type) bool {
+	switch tipe.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// Returns the name of the type displayed in the help
+// using the metavar of the types returned by lookup.
+func metavarOf(tipe reflect.Type, lookup func(reflect.Type) *valueType) string {
+	if valueType := lookup(tipe); valueType != nil {
+		if valueType.metavar != "" {
+			return valueType.metavar
+		}
+		return tipe.String()
+	}
+	switch tipe.Kind() {
+	case reflect.Slice:
+		return "[]" + metavarOf(tipe.Elem(), lookup)
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", tipe.Len(), metavarOf(tipe.Elem(), lookup))
+	case reflect.Map:
+		return fmt.Sprintf("map[%s]%s", metavarOf(tipe.Key(), lookup), metavarOf(tipe.Elem(), lookup))
+	case reflect.Ptr:
+		return "*" + metavarOf(tipe.Elem(), lookup)
+	}
+	return tipe.String()
+}
+
+// Returns a valueType parsing values by their kind
+// using range-checked conversions.
+func newKindValueType(tipe reflect.Type) *valueType {
+	return &valueType{
+		parse: func(p *parameter, value string) (reflect.Value, error) {
+			return parseKind(tipe, value)
+		},
+		format: formatSprint,
+	}
+}
+
+// Returns a valueType using the encoding.TextUnmarshaler
+// and encoding.TextMarshaler implementations of the type.
+func newTextValueType(tipe reflect.Type) *valueType {
+	return &valueType{
+		parse: func(p *parameter, value string) (reflect.Value, error) {
+			unmarshaler := reflect.New(tipe)
+			err := unmarshaler.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+			return unmarshaler.Elem(), err
+		},
+		format: func(p *parameter, value reflect.Value) string {
+			if !reflect.PtrTo(tipe).Implements(textMarshalerType) {
+				return formatSprint(p, value)
+			}
+			marshaler := reflect.New(tipe)
+			marshaler.Elem().Set(value)
+			text, err := marshaler.Interface().(encoding.TextMarshaler).MarshalText()
+			if err != nil {
+				return formatSprint(p, value)
+			}
+			return string(text)
+		},
+	}
+}
+
+// Formats a value with its default format, the String
+// method of its pointer is used for addressable values.
+func formatSprint(p *parameter, value reflect.Value) string {
+	if value.CanAddr() {
+		if stringer, ok := value.Addr().Interface().(fmt.Stringer); ok {
+			return stringer.String()
+		}
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// Parses a value of type tipe by its kind
+// using range-checked conversions.
+func parseKind(tipe reflect.Type, value string) (reflect.Value, error) {
+	parsed := reflect.New(tipe).Elem()
+	switch tipe.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return parsed, err
+		}
+		parsed.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, tipe.Bits())
+		if err != nil {
+			return parsed, err
+		}
+		parsed.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, tipe.Bits())
+		if err != nil {
+			return parsed, err
+		}
+		parsed.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, tipe.Bits())
+		if err != nil {
+			return parsed, err
+		}
+		parsed.SetFloat(f)
+	case reflect.String:
+		parsed.SetString(value)
+	default:
+		return parsed, fmt.Errorf("Incompatible type %s", tipe)
+	}
+	return parsed, nil
+}
+
+// Returns if the type is a supported scalar type.
+func isScalarType(tipe reflect.Type) bool {
+	return lookupType(tipe) != nil
+}
+
+// Returns if the type is parsed by the encoding.TextUnmarshaler
+// implementation of its pointer type.
+func isTextType(tipe reflect.Type) bool {
+	return reflect.PtrTo(tipe).Implements(textUnmarshalerType)
+}
+
+// Returns if the type is a slice or a fixed-size
+// array of a supported scalar type.
+func isArrayType(tipe reflect.Type) bool {
+	kind := tipe.Kind()
+	return (kind == reflect.Slice || kind == reflect.Array) &&
+		!isScalarType(tipe) && isScalarType(tipe.Elem())
+}
+
+// Returns if the type is a map of supported scalar types.
+func isMapType(tipe reflect.Type) bool {
+	return tipe.Kind() == reflect.Map && !isScalarType(tipe) &&
+		isScalarType(tipe.Key()) && isScalarType(tipe.Elem())
+}
+
+// Returns if the type is a pointer to a supported scalar
+// or slice type.
+func isPointerType(tipe reflect.Type) bool {
+	return tipe.Kind() == reflect.Ptr && !isScalarType(tipe) &&
+		(isScalarType(tipe.Elem()) || isArrayType(tipe.Elem()))
+}
+
+// Returns if the type is a supported scalar type, a slice
+// or an array of a supported scalar type, a pointer to them
+// or a map of supported scalar types.
+func isSupportedType(tipe reflect.Type) bool {
+	return isScalarType(tipe) || isArrayType(tipe) ||
+		isPointerType(tipe) || isMapType(tipe)
+}
@@ -0,0 +1,139 @@
+package yagclif
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSupportedType(t *testing.T) {
+	t.Run("positives", func(t *testing.T) {
+		for _, value := range []interface{}{
+			true, "", 1, int8(1), int16(1), int32(1), int64(1),
+			uint(1), uint8(1), uint16(1), uint32(1), uint64(1),
+			float32(1), float64(1), []string{}, []int{}, []float64{}, []uint8{},
+			new(int), new(string), new(bool), new([]int),
+			map[string]string{}, map[string]int{}, map[int]float64{},
+			[]bool{}, []time.Duration{}, [3]int{}, new([2]string),
+		} {
+			assert.True(t, isSupportedType(reflect.TypeOf(value)), "%T", value)
+		}
+	})
+	t.Run("negatives", func(t *testing.T) {
+		for _, value := range []interface{}{
+			struct{}{}, [][]int{}, [2][]int{}, complex64(1), make(chan int),
+			new(*int), new(struct{}), map[string][]int{}, new(map[string]string),
+		} {
+			assert.False(t, isSupportedType(reflect.TypeOf(value)), "%T", value)
+		}
+	})
+}
+
+// Type taught to the parser with RegisterType.
+type registeredPoint struct {
+	X, Y int
+}
+
+func TestRegisterType(t *testing.T) {
+	pointType := reflect.TypeOf(registeredPoint{})
+	parsePoint := func(value string) (interface{}, error) {
+		point := registeredPoint{}
+		_, err := fmt.Sscanf(value, "%dx%d", &point.X, &point.Y)
+		return point, err
+	}
+	formatPoint := func(value interface{}) string {
+		point := value.(registeredPoint)
+		return fmt.Sprintf("%dx%d", point.X, point.Y)
+	}
+	t.Run("returns error on nil arguments", func(t *testing.T) {
+		assert.NotNil(t, RegisterType(nil, parsePoint, formatPoint))
+		assert.NotNil(t, RegisterType(pointType, nil, formatPoint))
+		assert.False(t, isSupportedType(pointType))
+	})
+	t.Run("works", func(t *testing.T) {
+		assert.Nil(t, RegisterType(pointType, parsePoint, formatPoint))
+		assert.True(t, isScalarType(pointType))
+		assert.True(t, isArrayType(reflect.TypeOf([]registeredPoint{})))
+		type foo struct {
+			Point  registeredPoint   `yagclif:"default:+1x+2"`
+			Points []registeredPoint `yagclif:"delimiter:,"`
+		}
+		params, err := newParameters(reflect.TypeOf(foo{}))
+		assert.Nil(t, err)
+		fooVar := &foo{}
+		remaining, err := params.ParseArguments(fooVar, []string{"--points", "1x1,2x2"})
+		assert.Nil(t, err)
+		assert.Empty(t, remaining)
+		assert.Equal(t, &foo{
+			Point:  registeredPoint{1, 2},
+			Points: []registeredPoint{{1, 1}, {2, 2}},
+		}, fooVar)
+		assert.Contains(t, params[0].GetHelp(), "default=1x2")
+		_, err = params.ParseArguments(&foo{}, []string{"--points", "1,2"})
+		assert.NotNil(t, err)
+	})
+	t.Run("returns error on wrong parsed type", func(t *testing.T) {
+		type wrong struct{}
+		wrongType := reflect.TypeOf(wrong{})
+		assert.Nil(t, RegisterType(wrongType, func(value string) (interface{}, error) {
+			return value, nil
+		}, nil))
+		_, err := lookupType(wrongType).parse(&parameter{}, "hello")
+		assert.NotNil(t, err)
+	})
+}
+
+func TestNamedTypes(t *testing.T) {
+	type mode string
+	type level int
+	type ratio float32
+	type enabled bool
+	type foo struct {
+		Mode    mode `yagclif:"choices:fast|slow"`
+		Level   level
+		Ratios  []ratio `yagclif:"delimiter:,"`
+		Enabled enabled
+		Modes   map[mode]level
+	}
+	params, err := newParameters(reflect.TypeOf(foo{}))
+	assert.Nil(t, err)
+	assert.Len(t, params, 5)
+	fooVar := &foo{}
+	_, err = params.ParseArguments(fooVar, []string{
+		"--mode", "fast", "--level", "3", "--ratios", "0.5,1", "--enabled", "--modes", "slow=1",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &foo{
+		Mode:    "fast",
+		Level:   3,
+		Ratios:  []ratio{0.5, 1},
+		Enabled: true,
+		Modes:   map[mode]level{"slow": 1},
+	}, fooVar)
+	params, err = newParameters(reflect.TypeOf(foo{}))
+	assert.Nil(t, err)
+	_, err = params.ParseArguments(&foo{}, []string{"--level", "high"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "invalid value high for --level")
+}
+
+func TestParseKind(t *testing.T) {
+	t.Run("works", func(t *testing.T) {
+		value, err := parseKind(reflect.TypeOf(int32(0)), "-42")
+		assert.Nil(t, err)
+		assert.Equal(t, int32(-42), value.Interface())
+		value, err = parseKind(reflect.TypeOf(false), "true")
+		assert.Nil(t, err)
+		assert.Equal(t, true, value.Interface())
+	})
+	t.Run("returns errors", func(t *testing.T) {
+		_, err := parseKind(reflect.TypeOf(uint8(0)), "256")
+		assert.NotNil(t, err)
+		_, err = parseKind(reflect.TypeOf(struct{}{}), "")
+		assert.True(t, strings.HasPrefix(err.Error(), "Incompatible type"))
+	})
+}
@@ -81,6 +81,17 @@ func (app *App) RunWithArgs(args []string, outputHelpOnError bool) {
 
 // GetHelp return the help for the current cli app.
 func (app *App) GetHelp() string {
+	return app.help(false)
+}
+
+// GetVerboseHelp return the help for the current cli app
+// including the deprecated parameters.
+func (app *App) GetVerboseHelp() string {
+	return app.help(true)
+}
+
+// Returns the help of the routes and their parameters.
+func (app *App) help(verbose bool) string {
 	var buffer bytes.Buffer
 	writeln := func(s string) {
 		buffer.WriteString(s)
@@ -95,7 +106,7 @@ func (app *App) GetHelp() string {
 		if route.parameterType != nil {
 			writeln("\t\t usage :")
 		}
-		routeArgsHelp := route.getHelp()
+		routeArgsHelp := route.getHelp(verbose)
 		routeHelp := prependToArray(routeArgsHelp, "\t\t\t")
 		writeln(routeHelp)
 	}